@@ -2,7 +2,9 @@ package app
 
 import (
 	"context"
+	"errors"
 	"mainService/configs"
+	"mainService/pkg/textLanguage"
 
 	"github.com/gomodule/redigo/redis"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -25,11 +27,10 @@ func GetMongo() (*mongo.Client, error) {
 	return client, nil
 }
 
-func InitDBAndIndexes(cli *mongo.Client) (*mongo.Database, error) {
-	db := cli.Database("tlc")
-
-	serviceColl := db.Collection("service")
-	textIndex := mongo.IndexModel{
+// serviceTextIndex stems every service in the language stored in its
+// "language" field, Russian for the services without one.
+func serviceTextIndex() mongo.IndexModel {
+	return mongo.IndexModel{
 		Keys: bson.D{
 			{"title", "text"},
 			{"description", "text"},
@@ -40,10 +41,65 @@ func InitDBAndIndexes(cli *mongo.Client) (*mongo.Database, error) {
 				"title":       10,
 				"description": 5,
 			}).
-			SetDefaultLanguage("russian"),
+			SetDefaultLanguage(string(textLanguage.Russian)).
+			SetLanguageOverride("language"),
+	}
+}
+
+// RebuildServiceTextIndex recreates the text index, which MongoDB does not
+// allow to change in place.
+func RebuildServiceTextIndex(db *mongo.Database) error {
+	indexes := db.Collection("service").Indexes()
+
+	err := indexes.DropOne(context.TODO(), "textIndex")
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Name == "IndexNotFound") {
+		return err
 	}
 
-	_, err := serviceColl.Indexes().CreateOne(context.TODO(), textIndex)
+	_, err = indexes.CreateOne(context.TODO(), serviceTextIndex())
+	return err
+}
+
+func InitDBAndIndexes(cli *mongo.Client) (*mongo.Database, error) {
+	db := cli.Database("tlc")
+
+	serviceColl := db.Collection("service")
+	_, err := serviceColl.Indexes().CreateOne(context.TODO(), serviceTextIndex())
+	if err != nil {
+		return nil, err
+	}
+
+	animalsIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"animals", 1},
+			{"price", 1},
+		},
+		Options: options.Index().SetName("animalsIndex"),
+	}
+
+	priceIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"price", 1},
+		},
+		Options: options.Index().SetName("priceIndex"),
+	}
+
+	titleWordsIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"title_words", 1},
+		},
+		Options: options.Index().SetName("titleWordsIndex"),
+	}
+
+	titleTrigramsIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"title_trigrams", 1},
+		},
+		Options: options.Index().SetName("titleTrigramsIndex"),
+	}
+
+	_, err = serviceColl.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{animalsIndex, priceIndex, titleWordsIndex, titleTrigramsIndex})
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +119,161 @@ func InitDBAndIndexes(cli *mongo.Client) (*mongo.Database, error) {
 		return nil, err
 	}
 
+	hashIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"hash", 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetName("hashIndex"),
+	}
+
+	_, err = db.Collection("image_hash").Indexes().CreateOne(context.TODO(), hashIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Collection("blocked_image").Indexes().CreateOne(context.TODO(), hashIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingImageIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"status", 1},
+			{"created_at", 1},
+		},
+		Options: options.Index().SetName("statusIndex"),
+	}
+
+	_, err = db.Collection("pending_image").Indexes().CreateOne(context.TODO(), pendingImageIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"user", 1},
+			{"created_at", -1},
+		},
+		Options: options.Index().SetName("userIndex"),
+	}
+
+	_, err = db.Collection("notification").Indexes().CreateOne(context.TODO(), notificationIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	swearWordIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"word", 1},
+			{"allowed", 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetName("wordIndex"),
+	}
+
+	_, err = db.Collection("swear_word").Indexes().CreateOne(context.TODO(), swearWordIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reports go to the only open case of the target, which also keeps a
+	// user from reporting the same content twice, see AddReport.
+	openCaseIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"target", 1},
+			{"target_id", 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": "open"}).
+			SetName("openCaseIndex"),
+	}
+
+	caseQueueIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"status", 1},
+			{"report_count", -1},
+			{"updated_at", -1},
+		},
+		Options: options.Index().SetName("caseQueueIndex"),
+	}
+
+	_, err = db.Collection("moderation_case").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{openCaseIndex, caseQueueIndex})
+	if err != nil {
+		return nil, err
+	}
+
+	savedSearchIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"user", 1},
+			{"created_at", -1},
+		},
+		Options: options.Index().SetName("userIndex"),
+	}
+
+	savedSearchPriceIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"min_price", 1},
+			{"max_price", 1},
+		},
+		Options: options.Index().SetName("priceIndex"),
+	}
+
+	_, err = db.Collection("saved_search").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{savedSearchIndex, savedSearchPriceIndex})
+	if err != nil {
+		return nil, err
+	}
+
+	searchAlertIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"email", 1},
+			{"created_at", 1},
+		},
+		Options: options.Index().SetName("emailIndex"),
+	}
+
+	_, err = db.Collection("search_alert").Indexes().CreateOne(context.TODO(), searchAlertIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// A target is in the user's favorites once, see AddFavorite.
+	userFavoriteIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"user", 1},
+			{"target", 1},
+			{"target_id", 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetName("userTargetIndex"),
+	}
+
+	userFavoriteListIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"user", 1},
+			{"target", 1},
+			{"created_at", -1},
+		},
+		Options: options.Index().SetName("userListIndex"),
+	}
+
+	targetFavoriteIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{"target", 1},
+			{"target_id", 1},
+		},
+		Options: options.Index().SetName("targetIndex"),
+	}
+
+	_, err = db.Collection("favorite").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{userFavoriteIndex, userFavoriteListIndex, targetFavoriteIndex})
+	if err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
 
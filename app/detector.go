@@ -0,0 +1,39 @@
+package app
+
+import (
+	"mainService/configs"
+	"mainService/internal/repository/mongoTLC"
+	"mainService/internal/usecase"
+	"mainService/pkg/swearWordsDetector"
+)
+
+// GetSwearWordsDetector builds the detector from the configured files and the
+// words added by moderators, and starts watching them for changes.
+func GetSwearWordsDetector(swearWordRepo mongoTLC.ISwearWordRepository) (*swearWordsDetector.Detector, error) {
+	sources, err := swearWordsDetector.ParseFileSources(configs.SwearWordsSources, false)
+	if err != nil {
+		return nil, err
+	}
+
+	allowlist, err := swearWordsDetector.ParseFileSources(configs.SwearWordsAllowlist, true)
+	if err != nil {
+		return nil, err
+	}
+
+	sources = append(sources, allowlist...)
+	sources = append(sources, usecase.NewSwearWordSource(swearWordRepo))
+
+	confusables, err := swearWordsDetector.LoadConfusables(configs.SwearWordsConfusables)
+	if err != nil {
+		return nil, err
+	}
+
+	detector, err := swearWordsDetector.NewDetector(swearWordsDetector.NewNormalizer(confusables), sources...)
+	if err != nil {
+		return nil, err
+	}
+
+	detector.Watch(configs.SwearWordsReloadInterval, logError)
+
+	return detector, nil
+}
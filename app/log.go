@@ -0,0 +1,9 @@
+package app
+
+import "log"
+
+// logError reports the errors of the work done in the background, which has
+// no request to return them with.
+func logError(err error) {
+	log.Println(err)
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"fmt"
+
+	"mainService/configs"
+	"mainService/pkg/mailSender"
+)
+
+// GetMailSender returns nil when e-mails are off.
+func GetMailSender() (mailSender.Sender, error) {
+	switch configs.MailSenderKind {
+	case "none":
+		return nil, nil
+	case "log":
+		return mailSender.NewLogSender(), nil
+	case "smtp":
+		return mailSender.NewSMTPSender(mailSender.SMTPConfig{
+			Addr:     configs.SMTPConfig.Addr,
+			From:     configs.SMTPConfig.From,
+			User:     configs.SMTPConfig.User,
+			Password: configs.SMTPConfig.Password,
+		})
+	default:
+		return nil, fmt.Errorf("unknown mail sender %q: must be one of 'none', 'log', 'smtp'", configs.MailSenderKind)
+	}
+}
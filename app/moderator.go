@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/gomodule/redigo/redis"
+
+	"mainService/configs"
+	"mainService/internal/domain"
+	"mainService/internal/repository/redisTLC"
+	"mainService/internal/usecase"
+	"mainService/pkg/nsfwFilter"
+	"mainService/pkg/resilience"
+)
+
+// GetImageModerator builds the configured moderator and wraps it with guard.
+func GetImageModerator(redisDB *redis.Pool, guard *resilience.Guard) (nsfwFilter.ImageModerator, error) {
+	moderator, err := getBaseImageModerator(redisDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return nsfwFilter.NewResilientModerator(moderator, guard), nil
+}
+
+func getBaseImageModerator(redisDB *redis.Pool) (nsfwFilter.ImageModerator, error) {
+	switch configs.ImageModeratorKind {
+	case "amqp":
+		client := nsfwFilter.NewClient(nsfwFilter.ClientConfig{
+			URL:          configs.NSFWRabbitConfig.GetConnectionURI(),
+			QueueName:    configs.NSFWQueueName,
+			Timeout:      configs.NSFWTimeout,
+			PoolSize:     configs.NSFWChannelPoolSize,
+			Cache:        redisTLC.NewRedisVerdictCache(redisDB, configs.NSFWCacheTTL),
+			BatchWindow:  configs.NSFWBatchWindow,
+			MaxBatchSize: configs.NSFWMaxBatchSize,
+		})
+
+		return client, nil
+	case "allow":
+		return nsfwFilter.NewStubModerator(true), nil
+	case "deny":
+		return nsfwFilter.NewStubModerator(false), nil
+	case "hashlist":
+		return nsfwFilter.NewHashListModerator(
+			configs.ModeratorHashListConfig.AllowFile,
+			configs.ModeratorHashListConfig.DenyFile,
+			configs.ModeratorHashListConfig.DefaultIsSafe,
+		)
+	default:
+		return nil, fmt.Errorf("unknown image moderator %q: must be one of 'amqp', 'allow', 'deny', 'hashlist'", configs.ImageModeratorKind)
+	}
+}
+
+func GetNSFWPolicies() (map[domain.ImageTarget]nsfwFilter.Policy, error) {
+	specs := map[domain.ImageTarget]string{
+		domain.TargetAvatar:       configs.NSFWPolicies.Avatar,
+		domain.TargetBackground:   configs.NSFWPolicies.Background,
+		domain.TargetPetAvatar:    configs.NSFWPolicies.PetPhoto,
+		domain.TargetServicePhoto: configs.NSFWPolicies.ServicePhoto,
+	}
+
+	policies := make(map[domain.ImageTarget]nsfwFilter.Policy, len(specs))
+	for target, spec := range specs {
+		policy, err := nsfwFilter.ParsePolicy(spec)
+		if err != nil {
+			return nil, fmt.Errorf("nsfw policy for %s: %w", target, err)
+		}
+
+		policies[target] = policy
+	}
+
+	return policies, nil
+}
+
+func GetTextPolicies() (map[domain.TextField]usecase.TextFieldPolicy, error) {
+	policies := make(map[domain.TextField]usecase.TextFieldPolicy, len(configs.TextPolicies))
+	for field, spec := range configs.TextPolicies {
+		policy, err := usecase.ParseTextPolicy(spec)
+		if err != nil {
+			return nil, fmt.Errorf("text policy for %s: %w", field, err)
+		}
+
+		policies[domain.TextField(field)] = policy
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"fmt"
+
+	"mainService/configs"
+	"mainService/pkg/nsfwFilter"
+	"mainService/pkg/petAdviser"
+	"mainService/pkg/resilience"
+)
+
+func GetNSFWGuard() (*resilience.Guard, error) {
+	return newGuard("nsfw_filter", configs.NSFWResilience, nsfwFilter.IsTransient)
+}
+
+func GetAdviserGuard() (*resilience.Guard, error) {
+	return newGuard("adviser", configs.AdviserResilience, petAdviser.IsTransient)
+}
+
+func newGuard(name string, conf configs.ResilienceConfig, retryable func(error) bool) (*resilience.Guard, error) {
+	failMode, err := resilience.ParseFailMode(conf.FailMode)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return resilience.NewGuard(resilience.Config{
+		Name: name,
+		Retry: resilience.RetryPolicy{
+			Attempts:  conf.RetryAttempts,
+			BaseDelay: conf.RetryBaseDelay,
+			MaxDelay:  conf.RetryMaxDelay,
+		},
+		FailureThreshold: conf.FailureThreshold,
+		Cooldown:         conf.Cooldown,
+		FailMode:         failMode,
+		Retryable:        retryable,
+	}), nil
+}
@@ -2,8 +2,13 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
@@ -13,7 +18,7 @@ import (
 	"mainService/internal/repository/mongoTLC"
 	"mainService/internal/repository/redisTLC"
 	"mainService/internal/usecase"
-	"mainService/pkg/swearWordsDetector"
+	"mainService/pkg/petAdviser"
 )
 
 func Run() error {
@@ -23,11 +28,6 @@ func Run() error {
 
 	configs.InitConfigs()
 
-	err := swearWordsDetector.BuildAndCompileRegexp()
-	if err != nil {
-		return err
-	}
-
 	client, err := GetMongo()
 	if err != nil {
 		return err
@@ -37,6 +37,32 @@ func Run() error {
 	redisDB := GetRedis()
 	defer redisDB.Close()
 
+	nsfwGuard, err := GetNSFWGuard()
+	if err != nil {
+		return err
+	}
+
+	adviserGuard, err := GetAdviserGuard()
+	if err != nil {
+		return err
+	}
+
+	imageModerator, err := GetImageModerator(redisDB, nsfwGuard)
+	if err != nil {
+		return err
+	}
+	defer imageModerator.Close()
+
+	nsfwPolicies, err := GetNSFWPolicies()
+	if err != nil {
+		return err
+	}
+
+	mailer, err := GetMailSender()
+	if err != nil {
+		return err
+	}
+
 	db, err := InitDBAndIndexes(client)
 	if err != nil {
 		return err
@@ -45,24 +71,85 @@ func Run() error {
 	userRepo := mongoTLC.NewMongoUserRepository(db)
 	petRepo := mongoTLC.NewMongoPetRepository(db)
 	serviceRepo := mongoTLC.NewMongoServiceRepository(db)
+	imageHashRepo := mongoTLC.NewMongoImageHashRepository(db)
+	pendingImageRepo := mongoTLC.NewMongoPendingImageRepository(db)
+	notificationRepo := mongoTLC.NewMongoNotificationRepository(db)
+	swearWordRepo := mongoTLC.NewMongoSwearWordRepository(db)
+	caseRepo := mongoTLC.NewMongoModerationCaseRepository(db)
+	savedSearchRepo := mongoTLC.NewMongoSavedSearchRepository(db)
+	favoriteRepo := mongoTLC.NewMongoFavoriteRepository(db)
 	sessionRepo := redisTLC.NewRedisAuthRepository(redisDB)
 
-	userUsecase := usecase.NewUserUsecase(userRepo, sessionRepo)
-	petUsecase := usecase.NewPetUsecase(petRepo)
-	serviceUsecase := usecase.NewServiceUsecase(serviceRepo, userRepo, petRepo)
+	detector, err := GetSwearWordsDetector(swearWordRepo)
+	if err != nil {
+		return err
+	}
+	defer detector.Close()
+
+	moderationQueue := usecase.NewImageModerationQueue(pendingImageRepo, notificationRepo, imageHashRepo, imageModerator, nsfwPolicies, logError)
+	err = moderationQueue.Start(configs.ModerationWorkers)
+	if err != nil {
+		return err
+	}
+	defer moderationQueue.Close()
+
+	textPolicies, err := GetTextPolicies()
+	if err != nil {
+		return err
+	}
+	textModerator := usecase.NewTextModerator(detector, notificationRepo, textPolicies)
+
+	searchAlerts := usecase.NewSearchAlerts(savedSearchRepo, serviceRepo, notificationRepo, mailer, configs.SearchDigestInterval, logError)
+	searchAlerts.Start()
+	defer searchAlerts.Close()
+
+	userUsecase := usecase.NewUserUsecase(userRepo, sessionRepo, imageHashRepo, imageModerator, moderationQueue, textModerator)
+	petUsecase := usecase.NewPetUsecase(petRepo, petAdviser.NewClient(configs.AdviserURL, configs.AdviserTimeout), adviserGuard)
+	serviceUsecase := usecase.NewServiceUsecase(serviceRepo, userRepo, petRepo, favoriteRepo, imageHashRepo, imageModerator, moderationQueue, textModerator, searchAlerts)
+	moderationUsecase := usecase.NewModerationUsecase(imageHashRepo, swearWordRepo, moderationQueue, detector)
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepo)
+	statusUsecase := usecase.NewStatusUsecase(nsfwGuard, adviserGuard)
+	reportUsecase := usecase.NewReportUsecase(caseRepo, userRepo, notificationRepo)
+	savedSearchUsecase := usecase.NewSavedSearchUsecase(savedSearchRepo, userRepo, mailer)
+	favoriteUsecase := usecase.NewFavoriteUsecase(favoriteRepo, serviceRepo, userRepo)
 
 	router := mux.NewRouter()
 	deliveryHTTP.NewUserHandler(router, userUsecase)
 	deliveryHTTP.NewPetHandler(router, petUsecase)
 	deliveryHTTP.NewServiceHandler(router, serviceUsecase)
+	deliveryHTTP.NewModerationHandler(router, moderationUsecase)
+	deliveryHTTP.NewNotificationHandler(router, notificationUsecase)
+	deliveryHTTP.NewStatusHandler(router, statusUsecase)
+	deliveryHTTP.NewReportHandler(router, reportUsecase)
+	deliveryHTTP.NewSavedSearchHandler(router, savedSearchUsecase)
+	deliveryHTTP.NewFavoriteHandler(router, favoriteUsecase)
 
 	http.Handle("/", router)
 
-	fmt.Printf("\tstarting server at %s\n", ":8081")
-
-	err = http.ListenAndServe(configs.PORT, nil)
-	if err != nil {
-		return err
+	server := &http.Server{Addr: configs.PORT}
+
+	shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		fmt.Printf("\tstarting server at %s\n", configs.PORT)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	case <-shutdownCtx.Done():
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err = server.Shutdown(ctx)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
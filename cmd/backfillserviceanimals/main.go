@@ -0,0 +1,40 @@
+// Command backfillserviceanimals fills the animals of the services stored
+// before services kept the animal types of their pets, and of the services
+// left with none by an earlier backfill.
+// Run it from the repository root: go run ./cmd/backfillserviceanimals
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"mainService/app"
+	"mainService/configs"
+	"mainService/internal/repository/mongoTLC"
+)
+
+func main() {
+	if err := godotenv.Load("configs/.env"); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	configs.InitConfigs()
+
+	client, err := app.GetMongo()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer client.Disconnect(context.TODO())
+
+	err = mongoTLC.BackfillServiceAnimals(client.Database("tlc"))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println("filled the animals of the services")
+}
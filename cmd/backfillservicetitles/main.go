@@ -0,0 +1,39 @@
+// Command backfillservicetitles fills the title words and trigrams used by
+// autocomplete and typo search for the services stored before them.
+// Run it from the repository root: go run ./cmd/backfillservicetitles
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"mainService/app"
+	"mainService/configs"
+	"mainService/internal/repository/mongoTLC"
+)
+
+func main() {
+	if err := godotenv.Load("configs/.env"); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	configs.InitConfigs()
+
+	client, err := app.GetMongo()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer client.Disconnect(context.TODO())
+
+	err = mongoTLC.BackfillServiceTitles(client.Database("tlc"))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println("filled the title words and trigrams of the services")
+}
@@ -0,0 +1,43 @@
+// Command mergeanimals moves the animal types stored as typed by users onto
+// the IDs of the animal taxonomy, merging the rows of the animal collection
+// that spell the same animal differently.
+// Run it from the repository root: go run ./cmd/mergeanimals
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"mainService/app"
+	"mainService/configs"
+	"mainService/internal/repository/mongoTLC"
+)
+
+func main() {
+	if err := godotenv.Load("configs/.env"); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	configs.InitConfigs()
+
+	client, err := app.GetMongo()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer client.Disconnect(context.TODO())
+
+	result, err := mongoTLC.MergeAnimalVariants(client.Database("tlc"))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("set the animal of %d pets\n", result.Pets)
+	fmt.Printf("merged %d animal rows into others\n", result.MergedAnimals)
+	fmt.Printf("updated the animals of %d saved searches\n", result.SavedSearches)
+}
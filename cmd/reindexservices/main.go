@@ -0,0 +1,53 @@
+// Command reindexservices detects the language of the services stored before
+// services got one and rebuilds the text index so that they are stemmed in
+// it. Pass -redetect to detect the language of every service again.
+// Run it from the repository root: go run ./cmd/reindexservices
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"mainService/app"
+	"mainService/configs"
+	"mainService/internal/repository/mongoTLC"
+)
+
+func main() {
+	redetect := flag.Bool("redetect", false, "detect the language of all the services, not only of those without one")
+	flag.Parse()
+
+	if err := godotenv.Load("configs/.env"); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	configs.InitConfigs()
+
+	client, err := app.GetMongo()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer client.Disconnect(context.TODO())
+
+	db := client.Database("tlc")
+
+	updated, err := mongoTLC.BackfillServiceLanguages(db, *redetect)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("detected the language of %d services\n", updated)
+
+	err = app.RebuildServiceTextIndex(db)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println("rebuilt the text index")
+}
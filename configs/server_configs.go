@@ -2,6 +2,9 @@ package configs
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var PORT = ":"
@@ -18,6 +21,127 @@ var MainMongoConfig = dbConfig{}
 
 var AuthRedisConfig = dbConfig{}
 
+type rabbitConfig struct {
+	dbConfig
+	user     string
+	password string
+}
+
+var NSFWRabbitConfig = rabbitConfig{}
+
+var ImageModeratorKind = "amqp"
+
+var ModeratorHashListConfig = struct {
+	AllowFile     string
+	DenyFile      string
+	DefaultIsSafe bool
+}{}
+
+var NSFWQueueName = "nsfw_validation_queue"
+
+var NSFWTimeout = 15 * time.Second
+
+var NSFWChannelPoolSize = 4
+
+var NSFWBatchWindow = 20 * time.Millisecond
+
+var NSFWMaxBatchSize = 16
+
+var AsyncModeration = false
+
+var ModerationWorkers = 4
+
+var NSFWPolicies = struct {
+	Avatar       string
+	Background   string
+	PetPhoto     string
+	ServicePhoto string
+}{
+	Avatar:       "0.5:allow,reject",
+	Background:   "0.5:allow,reject",
+	PetPhoto:     "0.5:allow,reject",
+	ServicePhoto: "0.5:allow,reject",
+}
+
+type ResilienceConfig struct {
+	RetryAttempts    int
+	RetryBaseDelay   time.Duration
+	RetryMaxDelay    time.Duration
+	FailureThreshold int
+	Cooldown         time.Duration
+	FailMode         string
+}
+
+var NSFWResilience = ResilienceConfig{
+	RetryAttempts:    2,
+	RetryBaseDelay:   200 * time.Millisecond,
+	RetryMaxDelay:    2 * time.Second,
+	FailureThreshold: 5,
+	Cooldown:         30 * time.Second,
+	FailMode:         "closed",
+}
+
+var AdviserURL = "http://127.0.0.1:8000/get_advice"
+
+var AdviserTimeout = 30 * time.Second
+
+var AdviserResilience = ResilienceConfig{
+	RetryAttempts:    3,
+	RetryBaseDelay:   200 * time.Millisecond,
+	RetryMaxDelay:    2 * time.Second,
+	FailureThreshold: 5,
+	Cooldown:         30 * time.Second,
+	FailMode:         "open",
+}
+
+// TextPolicies tell for every user text field what is done with swear words
+// and spam, see usecase.ParseTextPolicy. Fields left out are not checked.
+var TextPolicies = map[string]string{
+	"user.login":          "swears:reject",
+	"user.username":       "swears:reject,spam:reject",
+	"user.contacts":       "swears:reject",
+	"pet.name":            "swears:reject,spam:reject",
+	"pet.type_of_animal":  "swears:reject,spam:reject",
+	"pet.info":            "swears:reject,spam:flag",
+	"service.title":       "swears:reject,spam:reject",
+	"service.description": "swears:reject,spam:flag",
+}
+
+var SwearWordsSources = "en:assets/swears/english_swears.txt,ru:assets/swears/russian_swears.txt"
+
+var SwearWordsAllowlist = "any:assets/swears/allowlist.txt"
+
+var SwearWordsConfusables = "assets/swears/confusables.txt"
+
+var SwearWordsReloadInterval = 30 * time.Second
+
+var ModeratorIDs = map[string]struct{}{}
+
+var ImageHashThreshold = 10
+
+var NSFWCacheTTL = time.Hour
+
+// ReportHideThreshold is the number of reports on an open case after which
+// the content is hidden until a moderator looks at it.
+var ReportHideThreshold = 5
+
+var DefaultSuspendDays = 7
+
+var MaxSavedSearches = 20
+
+// SearchDigestInterval is how often the matches of saved searches are sent
+// by e-mail, one digest per address.
+var SearchDigestInterval = time.Hour
+
+var MailSenderKind = "none"
+
+var SMTPConfig = struct {
+	Addr     string
+	From     string
+	User     string
+	Password string
+}{}
+
 func InitConfigs() {
 	PORT = PORT + os.Getenv("MAIN_SERVICE_PORT")
 
@@ -28,8 +152,107 @@ func InitConfigs() {
 	AuthRedisConfig.protocol = os.Getenv("REDIS_PROTOCOL")
 	AuthRedisConfig.host = os.Getenv("REDIS_HOST")
 	AuthRedisConfig.port = os.Getenv("REDIS_PORT")
+
+	NSFWRabbitConfig.protocol = os.Getenv("RABBITMQ_PROTOCOL")
+	NSFWRabbitConfig.host = os.Getenv("RABBITMQ_HOST")
+	NSFWRabbitConfig.port = os.Getenv("RABBITMQ_PORT")
+	NSFWRabbitConfig.user = os.Getenv("RABBITMQ_USER")
+	NSFWRabbitConfig.password = os.Getenv("RABBITMQ_PASSWORD")
+
+	if kind := os.Getenv("IMAGE_MODERATOR"); kind != "" {
+		ImageModeratorKind = kind
+	}
+	ModeratorHashListConfig.AllowFile = os.Getenv("MODERATOR_ALLOW_HASHES_FILE")
+	ModeratorHashListConfig.DenyFile = os.Getenv("MODERATOR_DENY_HASHES_FILE")
+	ModeratorHashListConfig.DefaultIsSafe = os.Getenv("MODERATOR_HASHES_DEFAULT") != "deny"
+
+	if queueName := os.Getenv("NSFW_QUEUE_NAME"); queueName != "" {
+		NSFWQueueName = queueName
+	}
+	NSFWTimeout = time.Duration(getIntEnv("NSFW_TIMEOUT_SECONDS", int(NSFWTimeout.Seconds()))) * time.Second
+	NSFWChannelPoolSize = getIntEnv("NSFW_CHANNEL_POOL_SIZE", NSFWChannelPoolSize)
+	NSFWBatchWindow = time.Duration(getIntEnv("NSFW_BATCH_WINDOW_MS", int(NSFWBatchWindow.Milliseconds()))) * time.Millisecond
+	NSFWMaxBatchSize = getIntEnv("NSFW_MAX_BATCH_SIZE", NSFWMaxBatchSize)
+
+	for _, id := range strings.Split(os.Getenv("MODERATOR_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ModeratorIDs[id] = struct{}{}
+		}
+	}
+
+	AsyncModeration = os.Getenv("MODERATION_MODE") == "async"
+	ModerationWorkers = getIntEnv("MODERATION_WORKERS", ModerationWorkers)
+	NSFWPolicies.Avatar = getStringEnv("NSFW_POLICY_AVATAR", NSFWPolicies.Avatar)
+	NSFWPolicies.Background = getStringEnv("NSFW_POLICY_BACKGROUND", NSFWPolicies.Background)
+	NSFWPolicies.PetPhoto = getStringEnv("NSFW_POLICY_PET_PHOTO", NSFWPolicies.PetPhoto)
+	NSFWPolicies.ServicePhoto = getStringEnv("NSFW_POLICY_SERVICE_PHOTO", NSFWPolicies.ServicePhoto)
+
+	NSFWResilience.init("NSFW")
+
+	AdviserURL = getStringEnv("ADVISER_URL", AdviserURL)
+	AdviserTimeout = time.Duration(getIntEnv("ADVISER_TIMEOUT_SECONDS", int(AdviserTimeout.Seconds()))) * time.Second
+	AdviserResilience.init("ADVISER")
+
+	// SWEAR_WORDS_FREE_TEXT predates the text policies and is kept for the
+	// existing deployments.
+	if os.Getenv("SWEAR_WORDS_FREE_TEXT") == "mask" {
+		TextPolicies["pet.info"] = strings.Replace(TextPolicies["pet.info"], "swears:reject", "swears:mask", 1)
+		TextPolicies["service.description"] = strings.Replace(TextPolicies["service.description"], "swears:reject", "swears:mask", 1)
+	}
+	for field := range TextPolicies {
+		key := "TEXT_POLICY_" + strings.ToUpper(strings.ReplaceAll(field, ".", "_"))
+		TextPolicies[field] = getStringEnv(key, TextPolicies[field])
+	}
+	SwearWordsSources = getStringEnv("SWEAR_WORDS_SOURCES", SwearWordsSources)
+	SwearWordsAllowlist = getStringEnv("SWEAR_WORDS_ALLOWLIST", SwearWordsAllowlist)
+	SwearWordsConfusables = getStringEnv("SWEAR_WORDS_CONFUSABLES", SwearWordsConfusables)
+	SwearWordsReloadInterval = time.Duration(getIntEnv("SWEAR_WORDS_RELOAD_SECONDS", int(SwearWordsReloadInterval.Seconds()))) * time.Second
+
+	ImageHashThreshold = getIntEnv("IMAGE_HASH_THRESHOLD", ImageHashThreshold)
+	NSFWCacheTTL = time.Duration(getIntEnv("NSFW_CACHE_TTL_SECONDS", int(NSFWCacheTTL.Seconds()))) * time.Second
+
+	ReportHideThreshold = getIntEnv("REPORT_HIDE_THRESHOLD", ReportHideThreshold)
+	DefaultSuspendDays = getIntEnv("SUSPEND_DAYS", DefaultSuspendDays)
+
+	MaxSavedSearches = getIntEnv("MAX_SAVED_SEARCHES", MaxSavedSearches)
+	SearchDigestInterval = time.Duration(getIntEnv("SEARCH_DIGEST_MINUTES", int(SearchDigestInterval.Minutes()))) * time.Minute
+	MailSenderKind = getStringEnv("MAIL_SENDER", MailSenderKind)
+	SMTPConfig.Addr = os.Getenv("SMTP_ADDR")
+	SMTPConfig.From = os.Getenv("SMTP_FROM")
+	SMTPConfig.User = os.Getenv("SMTP_USER")
+	SMTPConfig.Password = os.Getenv("SMTP_PASSWORD")
+}
+
+func (conf *ResilienceConfig) init(prefix string) {
+	conf.RetryAttempts = getIntEnv(prefix+"_RETRY_ATTEMPTS", conf.RetryAttempts)
+	conf.RetryBaseDelay = time.Duration(getIntEnv(prefix+"_RETRY_BASE_DELAY_MS", int(conf.RetryBaseDelay.Milliseconds()))) * time.Millisecond
+	conf.RetryMaxDelay = time.Duration(getIntEnv(prefix+"_RETRY_MAX_DELAY_MS", int(conf.RetryMaxDelay.Milliseconds()))) * time.Millisecond
+	conf.FailureThreshold = getIntEnv(prefix+"_BREAKER_THRESHOLD", conf.FailureThreshold)
+	conf.Cooldown = time.Duration(getIntEnv(prefix+"_BREAKER_COOLDOWN_SECONDS", int(conf.Cooldown.Seconds()))) * time.Second
+	conf.FailMode = getStringEnv(prefix+"_FAIL_MODE", conf.FailMode)
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+func getStringEnv(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return defaultValue
 }
 
 func (conf dbConfig) GetConnectionURI() string {
 	return conf.protocol + "://" + conf.host + ":" + conf.port
 }
+
+func (conf rabbitConfig) GetConnectionURI() string {
+	return conf.protocol + "://" + conf.user + ":" + conf.password + "@" + conf.host + ":" + conf.port + "/"
+}
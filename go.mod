@@ -2,16 +2,16 @@ module mainService
 
 go 1.21.4
 
-require github.com/gorilla/mux v1.8.1
-
 require (
-	github.com/gomodule/redigo v1.9.2 // indirect
-	github.com/google/uuid v1.6.0 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
-	github.com/rabbitmq/amqp091-go v1.10.0 // indirect
-	golang.org/x/sys v0.28.0 // indirect
+	github.com/gomodule/redigo v1.9.2
+	github.com/google/uuid v1.6.0
+	github.com/gorilla/mux v1.8.1
+	github.com/joho/godotenv v1.5.1
+	github.com/rabbitmq/amqp091-go v1.10.0
 )
 
+require golang.org/x/sys v0.28.0 // indirect
+
 require (
 	github.com/golang/snappy v0.0.4 // indirect
 	github.com/klauspost/compress v1.13.6 // indirect
@@ -20,8 +20,8 @@ require (
 	github.com/xdg-go/stringprep v1.0.4 // indirect
 	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
 	go.mongodb.org/mongo-driver v1.17.1
-	go.mongodb.org/mongo-driver/v2 v2.0.0-beta2 // indirect
-	golang.org/x/crypto v0.30.0 // indirect
+	go.mongodb.org/mongo-driver/v2 v2.0.0-beta2
+	golang.org/x/crypto v0.30.0
 	golang.org/x/sync v0.10.0 // indirect
-	golang.org/x/text v0.21.0 // indirect
+	golang.org/x/text v0.21.0
 )
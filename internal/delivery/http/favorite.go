@@ -0,0 +1,130 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+	"mainService/internal/usecase"
+	"mainService/pkg/responseTemplates"
+)
+
+type FavoriteHandler struct {
+	favoriteUsecase usecase.IFavoriteUsecase
+}
+
+func NewFavoriteHandler(router *mux.Router, favoriteUCase usecase.IFavoriteUsecase) {
+	handler := &FavoriteHandler{
+		favoriteUsecase: favoriteUCase,
+	}
+
+	router.HandleFunc("/add_favorite/{userID}", handler.AddFavorite).Methods("POST")
+	router.HandleFunc("/delete_favorite", handler.DeleteFavorite).Methods("DELETE")
+	router.HandleFunc("/get_favorite_services/{userID}", handler.GetFavoriteServices).Methods("GET")
+	router.HandleFunc("/get_favorite_providers/{userID}", handler.GetFavoriteProviders).Methods("GET")
+}
+
+func (h *FavoriteHandler) AddFavorite(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, ok := vars["userID"]
+	if !ok {
+		_ = responseTemplates.SendErrorMessage(w, MISSING_USER_ID, http.StatusBadRequest)
+		return
+	}
+
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	favorite := new(domain.ApiFavorite)
+	err = json.Unmarshal(body, favorite)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	err = h.favoriteUsecase.AddFavorite(userID, favorite)
+	if errors.Is(err, mongoTLC.NOT_FOUND) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotFound)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *FavoriteHandler) DeleteFavorite(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	userID := q.Get("userID")
+	favorite := &domain.ApiFavorite{
+		Target:   domain.FavoriteTarget(q.Get("target")),
+		TargetID: q.Get("targetID"),
+	}
+
+	if userID == "" || favorite.Target == "" || favorite.TargetID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	err := h.favoriteUsecase.DeleteFavorite(userID, favorite)
+	if errors.Is(err, mongoTLC.NOT_FOUND) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotFound)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *FavoriteHandler) GetFavoriteServices(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, ok := vars["userID"]
+	if !ok {
+		_ = responseTemplates.SendErrorMessage(w, MISSING_USER_ID, http.StatusBadRequest)
+		return
+	}
+
+	services, err := h.favoriteUsecase.GetFavoriteServices(userID)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonServices, _ := json.Marshal(services)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonServices)
+}
+
+func (h *FavoriteHandler) GetFavoriteProviders(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, ok := vars["userID"]
+	if !ok {
+		_ = responseTemplates.SendErrorMessage(w, MISSING_USER_ID, http.StatusBadRequest)
+		return
+	}
+
+	providers, err := h.favoriteUsecase.GetFavoriteProviders(userID)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonProviders, _ := json.Marshal(providers)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonProviders)
+}
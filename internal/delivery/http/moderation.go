@@ -0,0 +1,293 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"mainService/internal/domain"
+	"mainService/internal/usecase"
+	"mainService/pkg/responseTemplates"
+)
+
+type ModerationHandler struct {
+	moderationUsecase usecase.IModerationUsecase
+}
+
+func NewModerationHandler(router *mux.Router, moderationUCase usecase.IModerationUsecase) {
+	handler := &ModerationHandler{
+		moderationUsecase: moderationUCase,
+	}
+
+	router.HandleFunc("/block_image", handler.BlockImage).Methods("POST")
+	router.HandleFunc("/get_image_hash_stats", handler.GetImageHashStats).Methods("GET")
+	router.HandleFunc("/get_nsfw_cache_stats", handler.GetNSFWCacheStats).Methods("GET")
+	router.HandleFunc("/get_review_queue", handler.GetReviewQueue).Methods("GET")
+	router.HandleFunc("/approve_image", handler.ApproveImage).Methods("POST")
+	router.HandleFunc("/reject_image", handler.RejectImage).Methods("POST")
+	router.HandleFunc("/get_pending_images/{userID}", handler.GetUserPendingImages).Methods("GET")
+	router.HandleFunc("/get_swear_words", handler.GetSwearWords).Methods("GET")
+	router.HandleFunc("/add_swear_word", handler.AddSwearWord).Methods("POST")
+	router.HandleFunc("/delete_swear_word", handler.DeleteSwearWord).Methods("DELETE")
+}
+
+func (h *ModerationHandler) BlockImage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	moderatorID := q.Get("moderatorID")
+
+	if moderatorID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	blockReq := new(domain.BlockImageRequest)
+	err = json.Unmarshal(body, blockReq)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	blockResp, err := h.moderationUsecase.BlockImage(moderatorID, blockReq)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonBlockResp, _ := json.Marshal(blockResp)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonBlockResp)
+}
+
+func (h *ModerationHandler) GetImageHashStats(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	moderatorID := q.Get("moderatorID")
+
+	if moderatorID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.moderationUsecase.GetImageHashStats(moderatorID)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	jsonStats, _ := json.Marshal(stats)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonStats)
+}
+
+func (h *ModerationHandler) GetNSFWCacheStats(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	moderatorID := q.Get("moderatorID")
+
+	if moderatorID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.moderationUsecase.GetNSFWCacheStats(moderatorID)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	jsonStats, _ := json.Marshal(stats)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonStats)
+}
+
+func (h *ModerationHandler) GetReviewQueue(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	moderatorID := q.Get("moderatorID")
+
+	if moderatorID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	images, err := h.moderationUsecase.GetReviewQueue(moderatorID)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	jsonImages, _ := json.Marshal(images)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonImages)
+}
+
+func (h *ModerationHandler) ApproveImage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	moderatorID := q.Get("moderatorID")
+	imageID := q.Get("imageID")
+
+	if moderatorID == "" || imageID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	err := h.moderationUsecase.ApproveImage(moderatorID, imageID)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ModerationHandler) RejectImage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	moderatorID := q.Get("moderatorID")
+	imageID := q.Get("imageID")
+
+	if moderatorID == "" || imageID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	err := h.moderationUsecase.RejectImage(moderatorID, imageID)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ModerationHandler) GetUserPendingImages(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, ok := vars["userID"]
+	if !ok {
+		_ = responseTemplates.SendErrorMessage(w, MISSING_USER_ID, http.StatusBadRequest)
+		return
+	}
+
+	images, err := h.moderationUsecase.GetUserPendingImages(userID)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonImages, _ := json.Marshal(images)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonImages)
+}
+
+func (h *ModerationHandler) GetSwearWords(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	moderatorID := q.Get("moderatorID")
+
+	if moderatorID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	words, err := h.moderationUsecase.GetSwearWords(moderatorID)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	jsonWords, _ := json.Marshal(words)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonWords)
+}
+
+func (h *ModerationHandler) AddSwearWord(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	moderatorID := q.Get("moderatorID")
+
+	if moderatorID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	word := new(domain.ApiSwearWord)
+	err = json.Unmarshal(body, word)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	err = h.moderationUsecase.AddSwearWord(moderatorID, word)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ModerationHandler) DeleteSwearWord(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	moderatorID := q.Get("moderatorID")
+	word := q.Get("word")
+	allowed := q.Get("allowed") == "true"
+
+	if moderatorID == "" || word == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	err := h.moderationUsecase.DeleteSwearWord(moderatorID, word, allowed)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
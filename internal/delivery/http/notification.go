@@ -0,0 +1,61 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"mainService/internal/usecase"
+	"mainService/pkg/responseTemplates"
+)
+
+type NotificationHandler struct {
+	notificationUsecase usecase.INotificationUsecase
+}
+
+func NewNotificationHandler(router *mux.Router, notificationUCase usecase.INotificationUsecase) {
+	handler := &NotificationHandler{
+		notificationUsecase: notificationUCase,
+	}
+
+	router.HandleFunc("/get_notifications/{userID}", handler.GetNotifications).Methods("GET")
+	router.HandleFunc("/read_notifications/{userID}", handler.MarkNotificationsRead).Methods("PUT")
+}
+
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, ok := vars["userID"]
+	if !ok {
+		_ = responseTemplates.SendErrorMessage(w, MISSING_USER_ID, http.StatusBadRequest)
+		return
+	}
+
+	notifications, err := h.notificationUsecase.GetUserNotifications(userID)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonNotifications, _ := json.Marshal(notifications)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonNotifications)
+}
+
+func (h *NotificationHandler) MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, ok := vars["userID"]
+	if !ok {
+		_ = responseTemplates.SendErrorMessage(w, MISSING_USER_ID, http.StatusBadRequest)
+		return
+	}
+
+	err := h.notificationUsecase.MarkNotificationsRead(userID)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -2,14 +2,16 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
+	"strconv"
 
 	"github.com/gorilla/mux"
 
 	"mainService/internal/usecase"
+	"mainService/pkg/petAdviser"
+	"mainService/pkg/resilience"
 	"mainService/pkg/responseTemplates"
 )
 
@@ -24,6 +26,7 @@ func NewPetHandler(router *mux.Router, petUCase usecase.IPetUsecase) {
 
 	router.HandleFunc("/pet_info/{petID}", handler.GetPetInfo).Methods("GET")
 	router.HandleFunc("/get_top_animals", handler.GetTopAnimals).Methods("GET")
+	router.HandleFunc("/get_animal_taxonomy", handler.LookupAnimals).Methods("GET")
 	router.HandleFunc("/get_advice", handler.GetPetCareAdvice).Methods("GET")
 }
 
@@ -66,39 +69,62 @@ func (h *PetHandler) GetTopAnimals(w http.ResponseWriter, r *http.Request) {
 	w.Write(jsonResult)
 }
 
-func (h *PetHandler) GetPetCareAdvice(w http.ResponseWriter, r *http.Request) {
+func (h *PetHandler) LookupAnimals(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
-	animal := q.Get("animal")
-	prompt := q.Get("prompt")
 
-	if animal == "" || prompt == "" {
-		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+	limit := int64(0)
+	if rawLimit := q.Get("limit"); rawLimit != "" {
+		var err error
+		limit, err = strconv.ParseInt(rawLimit, 10, 64)
+		if err != nil {
+			_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+			return
+		}
+	}
+
+	animals, err := h.petUsecase.LookupAnimals(q.Get("query"), q.Get("lang"), limit)
+	if errors.Is(err, usecase.BAD_ANIMAL_LANGUAGE) || errors.Is(err, usecase.POSITIVE_NUMBER_REQUIRED) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusInternalServerError)
 		return
 	}
 
-	adviceURL := "http://127.0.0.1:8000/get_advice"
+	mapResult := map[string]interface{}{
+		"animals": animals,
+	}
 
-	params := url.Values{}
-	params.Add("animal", animal)
-	params.Add("prompt", prompt)
+	jsonResult, _ := json.Marshal(mapResult)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResult)
+}
 
-	fullAdviceURL := fmt.Sprintf("%s?%s", adviceURL, params.Encode())
+func (h *PetHandler) GetPetCareAdvice(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	animal := q.Get("animal")
+	prompt := q.Get("prompt")
 
-	resp, err := http.Get(fullAdviceURL)
-	if err != nil {
-		_ = responseTemplates.SendErrorMessage(w, err, http.StatusInternalServerError)
+	if animal == "" || prompt == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
 		return
 	}
 
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
+	advice, err := h.petUsecase.GetPetCareAdvice(animal, prompt)
+	if errors.Is(err, resilience.UNAVAILABLE_ERR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusServiceUnavailable)
+		return
+	} else if errors.Is(err, petAdviser.BAD_REQUEST) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	} else if err != nil {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusInternalServerError)
 		return
 	}
 
+	jsonAdvice, _ := json.Marshal(advice)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	w.Write(respBody)
+	w.Write(jsonAdvice)
 }
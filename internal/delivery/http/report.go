@@ -0,0 +1,167 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+	"mainService/internal/usecase"
+	"mainService/pkg/responseTemplates"
+)
+
+type ReportHandler struct {
+	reportUsecase usecase.IReportUsecase
+}
+
+func NewReportHandler(router *mux.Router, reportUCase usecase.IReportUsecase) {
+	handler := &ReportHandler{
+		reportUsecase: reportUCase,
+	}
+
+	router.HandleFunc("/report/{userID}", handler.ReportContent).Methods("POST")
+	router.HandleFunc("/get_moderation_cases", handler.GetCases).Methods("GET")
+	router.HandleFunc("/get_moderation_case/{caseID}", handler.GetCase).Methods("GET")
+	router.HandleFunc("/moderate_case", handler.ApplyCaseAction).Methods("POST")
+}
+
+func (h *ReportHandler) ReportContent(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, ok := vars["userID"]
+	if !ok {
+		_ = responseTemplates.SendErrorMessage(w, MISSING_USER_ID, http.StatusBadRequest)
+		return
+	}
+
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	report := new(domain.ApiReport)
+	err = json.Unmarshal(body, report)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	err = h.reportUsecase.ReportContent(userID, report)
+	if errors.Is(err, mongoTLC.NOT_FOUND) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotFound)
+		return
+	} else if errors.Is(err, mongoTLC.ALREADY_REPORTED) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusConflict)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ReportHandler) GetCases(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	moderatorID := q.Get("moderatorID")
+	status := domain.CaseStatus(q.Get("status"))
+
+	if moderatorID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	if status == "" {
+		status = domain.CaseOpen
+	}
+
+	cases, err := h.reportUsecase.GetCases(moderatorID, status)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	jsonCases, _ := json.Marshal(cases)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonCases)
+}
+
+func (h *ReportHandler) GetCase(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	caseID, ok := vars["caseID"]
+	moderatorID := r.URL.Query().Get("moderatorID")
+
+	if !ok || moderatorID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	modCase, err := h.reportUsecase.GetCase(moderatorID, caseID)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if errors.Is(err, mongoTLC.NOT_FOUND) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotFound)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonCase, _ := json.Marshal(modCase)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonCase)
+}
+
+func (h *ReportHandler) ApplyCaseAction(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	moderatorID := q.Get("moderatorID")
+
+	if moderatorID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	actionReq := new(domain.ApiCaseActionRequest)
+	err = json.Unmarshal(body, actionReq)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	err = h.reportUsecase.ApplyCaseAction(moderatorID, actionReq)
+	if errors.Is(err, usecase.NOT_A_MODERATOR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if errors.Is(err, mongoTLC.NOT_FOUND) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotFound)
+		return
+	} else if errors.Is(err, usecase.CASE_CLOSED) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusConflict)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
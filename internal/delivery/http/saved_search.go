@@ -0,0 +1,138 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+	"mainService/internal/usecase"
+	"mainService/pkg/responseTemplates"
+)
+
+type SavedSearchHandler struct {
+	savedSearchUsecase usecase.ISavedSearchUsecase
+}
+
+func NewSavedSearchHandler(router *mux.Router, savedSearchUCase usecase.ISavedSearchUsecase) {
+	handler := &SavedSearchHandler{
+		savedSearchUsecase: savedSearchUCase,
+	}
+
+	router.HandleFunc("/save_search/{userID}", handler.SaveSearch).Methods("POST")
+	router.HandleFunc("/get_saved_searches/{userID}", handler.GetSavedSearches).Methods("GET")
+	router.HandleFunc("/delete_saved_search", handler.DeleteSavedSearch).Methods("DELETE")
+	router.HandleFunc("/confirm_saved_search_email", handler.ConfirmEmail).Methods("POST")
+}
+
+func (h *SavedSearchHandler) SaveSearch(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, ok := vars["userID"]
+	if !ok {
+		_ = responseTemplates.SendErrorMessage(w, MISSING_USER_ID, http.StatusBadRequest)
+		return
+	}
+
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	search := new(domain.ApiSavedSearch)
+	err = json.Unmarshal(body, search)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	saved, err := h.savedSearchUsecase.SaveSearch(userID, search)
+	if errors.Is(err, usecase.USER_SUSPENDED) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if errors.Is(err, usecase.TOO_MANY_SAVED_SEARCHES) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusConflict)
+		return
+	} else if errors.Is(err, usecase.EMAILS_DISABLED) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotImplemented)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonSearch, _ := json.Marshal(saved)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonSearch)
+}
+
+func (h *SavedSearchHandler) GetSavedSearches(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, ok := vars["userID"]
+	if !ok {
+		_ = responseTemplates.SendErrorMessage(w, MISSING_USER_ID, http.StatusBadRequest)
+		return
+	}
+
+	searches, err := h.savedSearchUsecase.GetSavedSearches(userID)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonSearches, _ := json.Marshal(searches)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonSearches)
+}
+
+func (h *SavedSearchHandler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	userID := q.Get("userID")
+	searchID := q.Get("savedSearchID")
+
+	if userID == "" || searchID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	err := h.savedSearchUsecase.DeleteSavedSearch(userID, searchID)
+	if errors.Is(err, mongoTLC.NOT_FOUND) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotFound)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *SavedSearchHandler) ConfirmEmail(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	searchID := q.Get("savedSearchID")
+	token := q.Get("token")
+
+	if searchID == "" || token == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	err := h.savedSearchUsecase.ConfirmEmail(searchID, token)
+	if errors.Is(err, mongoTLC.NOT_FOUND) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotFound)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -6,9 +6,11 @@ import (
 	"io"
 	"mainService/internal/domain"
 	"mainService/internal/usecase"
+	"mainService/pkg/resilience"
 	"mainService/pkg/responseTemplates"
 	"mainService/pkg/serverErrors"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 )
@@ -28,6 +30,13 @@ func NewServiceHandler(router *mux.Router, serviceUCase usecase.IServiceUsecase)
 	router.HandleFunc("/get_all_services", handler.GetAllServices).Methods("GET")
 	router.HandleFunc("/delete_service", handler.DeleteService).Methods("DELETE")
 	router.HandleFunc("/search_services", handler.SearchServices).Methods("POST")
+	router.HandleFunc("/autocomplete", handler.Autocomplete).Methods("GET")
+	router.HandleFunc("/get_feed/{userID}", handler.GetFeed).Methods("GET")
+	router.HandleFunc("/get_similar_services/{serviceID}", handler.GetSimilarServices).Methods("GET")
+	router.HandleFunc("/add_service_photo", handler.AddServicePhoto).Methods("POST")
+	router.HandleFunc("/delete_service_photo", handler.DeleteServicePhoto).Methods("DELETE")
+	router.HandleFunc("/set_service_cover", handler.SetServiceCover).Methods("PUT")
+	router.HandleFunc("/reorder_service_photos", handler.ReorderServicePhotos).Methods("PUT")
 }
 
 func (h *ServiceHandler) AddService(w http.ResponseWriter, r *http.Request) {
@@ -54,9 +63,12 @@ func (h *ServiceHandler) AddService(w http.ResponseWriter, r *http.Request) {
 	}
 
 	serviceIDToSend, err := h.serviceUsecase.AddService(userID, newService)
-	if errors.Is(err, serverErrors.SWEAR_WORDS_ERROR) {
+	if errors.Is(err, serverErrors.SWEAR_WORDS_ERROR) || errors.Is(err, serverErrors.SPAM_ERROR) {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusUnprocessableEntity)
 		return
+	} else if errors.Is(err, usecase.USER_SUSPENDED) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
 	} else if err != nil {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
 		return
@@ -109,7 +121,9 @@ func (h *ServiceHandler) GetUserServices(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *ServiceHandler) GetAllServices(w http.ResponseWriter, r *http.Request) {
-	services, err := h.serviceUsecase.GetAllServices()
+	viewerID := r.URL.Query().Get("userID")
+
+	services, err := h.serviceUsecase.GetAllServices(viewerID)
 	if err != nil {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
 		return
@@ -143,6 +157,7 @@ func (h *ServiceHandler) DeleteService(w http.ResponseWriter, r *http.Request) {
 func (h *ServiceHandler) SearchServices(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	queryString := q.Get("query")
+	viewerID := q.Get("userID")
 
 	defer r.Body.Close()
 
@@ -159,14 +174,241 @@ func (h *ServiceHandler) SearchServices(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	services, err := h.serviceUsecase.SearchServices(queryString, serviceFilter)
+	result, err := h.serviceUsecase.SearchServices(queryString, serviceFilter, viewerID)
 	if err != nil {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
 		return
 	}
 
-	jsonServices, _ := json.Marshal(services)
+	// clients that do not ask for facets keep getting the bare list
+	var jsonServices []byte
+	if serviceFilter.Facets {
+		jsonServices, _ = json.Marshal(result)
+	} else {
+		jsonServices, _ = json.Marshal(result.Services)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	w.Write(jsonServices)
 }
+
+func (h *ServiceHandler) AddServicePhoto(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	userID := q.Get("userID")
+	serviceID := q.Get("serviceID")
+
+	if userID == "" || serviceID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	newPhoto := new(domain.ApiServicePhoto)
+	err = json.Unmarshal(body, newPhoto)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	photoIDToSend, err := h.serviceUsecase.AddServicePhoto(userID, serviceID, newPhoto)
+	if errors.Is(err, serverErrors.NSFW_CONTENT_SERVICE_PHOTO_ERROR) || errors.Is(err, serverErrors.BANNED_IMAGE_ERROR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotAcceptable)
+		return
+	} else if errors.Is(err, resilience.UNAVAILABLE_ERR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusServiceUnavailable)
+		return
+	} else if errors.Is(err, usecase.USER_SUSPENDED) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
+	} else if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonPhotoID, _ := json.Marshal(photoIDToSend)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonPhotoID)
+}
+
+func (h *ServiceHandler) DeleteServicePhoto(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	userID := q.Get("userID")
+	serviceID := q.Get("serviceID")
+	photoID := q.Get("photoID")
+
+	if userID == "" || serviceID == "" || photoID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	err := h.serviceUsecase.DeleteServicePhoto(userID, serviceID, photoID)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ServiceHandler) SetServiceCover(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	userID := q.Get("userID")
+	serviceID := q.Get("serviceID")
+	photoID := q.Get("photoID")
+
+	if userID == "" || serviceID == "" || photoID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	err := h.serviceUsecase.SetServiceCover(userID, serviceID, photoID)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ServiceHandler) ReorderServicePhotos(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	userID := q.Get("userID")
+	serviceID := q.Get("serviceID")
+
+	if userID == "" || serviceID == "" {
+		_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+		return
+	}
+
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	order := new(domain.ServicePhotoOrder)
+	err = json.Unmarshal(body, order)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, INVALID_BODY, http.StatusBadRequest)
+		return
+	}
+
+	err = h.serviceUsecase.ReorderServicePhotos(userID, serviceID, order)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ServiceHandler) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	input := q.Get("query")
+
+	limit := int64(0)
+	if rawLimit := q.Get("limit"); rawLimit != "" {
+		var err error
+		limit, err = strconv.ParseInt(rawLimit, 10, 64)
+		if err != nil {
+			_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+			return
+		}
+	}
+
+	suggestions, err := h.serviceUsecase.Autocomplete(input, limit)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonSuggestions, _ := json.Marshal(suggestions)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonSuggestions)
+}
+
+func (h *ServiceHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, ok := vars["userID"]
+	if !ok {
+		_ = responseTemplates.SendErrorMessage(w, MISSING_USER_ID, http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+
+	page := int64(0)
+	if rawPage := q.Get("page"); rawPage != "" {
+		var err error
+		page, err = strconv.ParseInt(rawPage, 10, 64)
+		if err != nil {
+			_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+			return
+		}
+	}
+
+	pageSize := int64(0)
+	if rawPageSize := q.Get("page_size"); rawPageSize != "" {
+		var err error
+		pageSize, err = strconv.ParseInt(rawPageSize, 10, 64)
+		if err != nil {
+			_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+			return
+		}
+	}
+
+	feed, err := h.serviceUsecase.GetFeed(userID, page, pageSize)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonFeed, _ := json.Marshal(feed)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonFeed)
+}
+
+func (h *ServiceHandler) GetSimilarServices(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	serviceID, ok := vars["serviceID"]
+	if !ok {
+		_ = responseTemplates.SendErrorMessage(w, BAD_GET_PARAMETER, http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	viewerID := q.Get("userID")
+
+	limit := int64(0)
+	if rawLimit := q.Get("limit"); rawLimit != "" {
+		var err error
+		limit, err = strconv.ParseInt(rawLimit, 10, 64)
+		if err != nil {
+			_ = responseTemplates.SendErrorMessage(w, BAD_QUERY_PARAMETERS, http.StatusBadRequest)
+			return
+		}
+	}
+
+	similar, err := h.serviceUsecase.GetSimilarServices(serviceID, limit, viewerID)
+	if err != nil {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
+		return
+	}
+
+	jsonSimilar, _ := json.Marshal(similar)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonSimilar)
+}
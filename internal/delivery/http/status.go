@@ -0,0 +1,33 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"mainService/internal/usecase"
+)
+
+type StatusHandler struct {
+	statusUsecase usecase.IStatusUsecase
+}
+
+func NewStatusHandler(router *mux.Router, statusUCase usecase.IStatusUsecase) {
+	handler := &StatusHandler{
+		statusUsecase: statusUCase,
+	}
+
+	router.HandleFunc("/get_integrations_status", handler.GetIntegrationsStatus).Methods("GET")
+}
+
+func (h *StatusHandler) GetIntegrationsStatus(w http.ResponseWriter, r *http.Request) {
+	mapResult := map[string]interface{}{
+		"integrations": h.statusUsecase.GetIntegrationsStatus(),
+	}
+
+	jsonResult, _ := json.Marshal(mapResult)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(jsonResult)
+}
@@ -11,6 +11,7 @@ import (
 
 	"mainService/internal/domain"
 	"mainService/internal/usecase"
+	"mainService/pkg/resilience"
 	"mainService/pkg/responseTemplates"
 	"mainService/pkg/serverErrors"
 )
@@ -52,10 +53,13 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	loginResp, err := h.userUsecase.AddUser(newUser)
-	if errors.Is(err, serverErrors.SWEAR_WORDS_ERROR) {
+	if errors.Is(err, serverErrors.SWEAR_WORDS_ERROR) || errors.Is(err, serverErrors.SPAM_ERROR) {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusUnprocessableEntity)
 		return
-	} else if errors.Is(err, serverErrors.NSFW_CONTENT_AVATAR_ERROR) || errors.Is(err, serverErrors.NSFW_CONTENT_BACK_IMAGE_ERROR) {
+	} else if errors.Is(err, resilience.UNAVAILABLE_ERR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusServiceUnavailable)
+		return
+	} else if errors.Is(err, serverErrors.NSFW_CONTENT_AVATAR_ERROR) || errors.Is(err, serverErrors.NSFW_CONTENT_BACK_IMAGE_ERROR) || errors.Is(err, serverErrors.BANNED_IMAGE_ERROR) {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotAcceptable)
 		return
 	} else if err != nil {
@@ -105,7 +109,9 @@ func (h *UserHandler) GetUserInfo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userInfo, err := h.userUsecase.GetUserInfo(userID)
+	viewerID := r.URL.Query().Get("viewerID")
+
+	userInfo, err := h.userUsecase.GetUserInfo(userID, viewerID)
 	if err != nil {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
 		fmt.Print(err)
@@ -142,12 +148,18 @@ func (h *UserHandler) UpdateUserInfo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = h.userUsecase.UpdateUser(userID, updInfo)
-	if errors.Is(err, serverErrors.SWEAR_WORDS_ERROR) {
+	if errors.Is(err, serverErrors.SWEAR_WORDS_ERROR) || errors.Is(err, serverErrors.SPAM_ERROR) {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusUnprocessableEntity)
 		return
-	} else if errors.Is(err, serverErrors.NSFW_CONTENT_AVATAR_ERROR) || errors.Is(err, serverErrors.NSFW_CONTENT_BACK_IMAGE_ERROR) {
+	} else if errors.Is(err, resilience.UNAVAILABLE_ERR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusServiceUnavailable)
+		return
+	} else if errors.Is(err, serverErrors.NSFW_CONTENT_AVATAR_ERROR) || errors.Is(err, serverErrors.NSFW_CONTENT_BACK_IMAGE_ERROR) || errors.Is(err, serverErrors.BANNED_IMAGE_ERROR) {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotAcceptable)
 		return
+	} else if errors.Is(err, usecase.USER_SUSPENDED) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
 	} else if err != nil {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
 		return
@@ -221,12 +233,18 @@ func (h *UserHandler) AddPet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	petIDToSend, err := h.userUsecase.AddPet(userID, newPet)
-	if errors.Is(err, serverErrors.SWEAR_WORDS_ERROR) {
+	if errors.Is(err, serverErrors.SWEAR_WORDS_ERROR) || errors.Is(err, serverErrors.SPAM_ERROR) {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusUnprocessableEntity)
 		return
-	} else if errors.Is(err, serverErrors.NSFW_CONTENT_AVATAR_ERROR) || errors.Is(err, serverErrors.NSFW_CONTENT_BACK_IMAGE_ERROR) {
+	} else if errors.Is(err, resilience.UNAVAILABLE_ERR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusServiceUnavailable)
+		return
+	} else if errors.Is(err, serverErrors.NSFW_CONTENT_AVATAR_ERROR) || errors.Is(err, serverErrors.NSFW_CONTENT_BACK_IMAGE_ERROR) || errors.Is(err, serverErrors.BANNED_IMAGE_ERROR) {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotAcceptable)
 		return
+	} else if errors.Is(err, usecase.USER_SUSPENDED) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
 	} else if err != nil {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
 		return
@@ -283,12 +301,18 @@ func (h *UserHandler) UpdatePet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = h.userUsecase.UpdatePet(userID, petID, updInfo)
-	if errors.Is(err, serverErrors.SWEAR_WORDS_ERROR) {
+	if errors.Is(err, serverErrors.SWEAR_WORDS_ERROR) || errors.Is(err, serverErrors.SPAM_ERROR) {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusUnprocessableEntity)
 		return
-	} else if errors.Is(err, serverErrors.NSFW_CONTENT_AVATAR_ERROR) || errors.Is(err, serverErrors.NSFW_CONTENT_BACK_IMAGE_ERROR) {
+	} else if errors.Is(err, resilience.UNAVAILABLE_ERR) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusServiceUnavailable)
+		return
+	} else if errors.Is(err, serverErrors.NSFW_CONTENT_AVATAR_ERROR) || errors.Is(err, serverErrors.NSFW_CONTENT_BACK_IMAGE_ERROR) || errors.Is(err, serverErrors.BANNED_IMAGE_ERROR) {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusNotAcceptable)
 		return
+	} else if errors.Is(err, usecase.USER_SUSPENDED) {
+		_ = responseTemplates.SendErrorMessage(w, err, http.StatusForbidden)
+		return
 	} else if err != nil {
 		_ = responseTemplates.SendErrorMessage(w, err, http.StatusBadRequest)
 		return
@@ -0,0 +1,10 @@
+package domain
+
+// ApiAnimal is an entry of the animal taxonomy: a species or a breed of the
+// species. AnimalID is what pets, services and the top animals refer to.
+type ApiAnimal struct {
+	AnimalID string   `json:"animal_id"`
+	Species  string   `json:"species,omitempty"`
+	Name     string   `json:"name"`
+	Synonyms []string `json:"synonyms"`
+}
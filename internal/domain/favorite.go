@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type FavoriteTarget string
+
+const (
+	FavoriteService  FavoriteTarget = "service"
+	FavoriteProvider FavoriteTarget = "provider"
+)
+
+func IsFavoriteTarget(target FavoriteTarget) bool {
+	return target == FavoriteService || target == FavoriteProvider
+}
+
+type ApiFavorite struct {
+	Target   FavoriteTarget `json:"target"`
+	TargetID string         `json:"target_id"`
+}
+
+type DBFavorite struct {
+	FavoriteID bson.ObjectID  `bson:"_id,omitempty"`
+	UserID     bson.ObjectID  `bson:"user"`
+	Target     FavoriteTarget `bson:"target"`
+	TargetID   bson.ObjectID  `bson:"target_id"`
+	CreatedAt  time.Time      `bson:"created_at"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+// FeedReason explains why a service is in the feed: the signal that added
+// the most to its score.
+type FeedReason string
+
+const (
+	// ReasonYourPets means the service is for the animals of the user's pets.
+	ReasonYourPets FeedReason = "your_pets"
+	// ReasonPopularAnimals replaces ReasonYourPets for users without pets,
+	// whose feed is built around the animals most services are for.
+	ReasonPopularAnimals FeedReason = "popular_animals"
+	ReasonTopRated       FeedReason = "top_rated"
+	ReasonNew            FeedReason = "new"
+	ReasonPopular        FeedReason = "popular"
+)
+
+type FeedItem struct {
+	Service        *ApiService `json:"service"`
+	Score          float64     `json:"score"`
+	Reason         FeedReason  `json:"reason"`
+	MatchedAnimals []string    `json:"matched_animals,omitempty"`
+}
+
+type Feed struct {
+	Items []*FeedItem `json:"items"`
+	// Personalized is false when the user has no pets to build the feed on.
+	Personalized bool `json:"personalized"`
+}
+
+// FeedScores are the signals of a feed item, each from 0 to 1.
+type FeedScores struct {
+	Animals    float64 `bson:"animals"`
+	Rating     float64 `bson:"rating"`
+	Recency    float64 `bson:"recency"`
+	Popularity float64 `bson:"popularity"`
+}
+
+type DBFeedItem struct {
+	DBServiceSerachResult `bson:",inline"`
+	MatchedAnimals        []string   `bson:"matched_animals"`
+	FeedScores            FeedScores `bson:"feed_scores"`
+	FeedScore             float64    `bson:"feed_score"`
+}
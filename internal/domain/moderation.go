@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type BlockImageRequest struct {
+	Image  string `json:"image,omitempty"`
+	Hash   string `json:"hash,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type BlockImageResponse struct {
+	Hash string `json:"hash"`
+}
+
+type DBBlockedImage struct {
+	Hash        int64         `bson:"hash"`
+	Reason      string        `bson:"reason,omitempty"`
+	ModeratorID bson.ObjectID `bson:"moderator,omitempty"`
+	Hits        int64         `bson:"hits"`
+}
+
+type ImageHashStats struct {
+	AcceptedImages    int64 `json:"accepted_images"`
+	UniqueImages      int64 `json:"unique_images"`
+	ExactDuplicates   int64 `json:"exact_duplicates"`
+	BlockedHashes     int64 `json:"blocked_hashes"`
+	BlockedRejections int64 `json:"blocked_rejections"`
+}
+
+type NSFWCacheStats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// TextField is a user input checked by the text moderator, written as
+// "<subject>.<json field>".
+type TextField string
+
+const (
+	FieldUserLogin          TextField = "user.login"
+	FieldUserUsername       TextField = "user.username"
+	FieldUserContacts       TextField = "user.contacts"
+	FieldPetName            TextField = "pet.name"
+	FieldPetTypeOfAnimal    TextField = "pet.type_of_animal"
+	FieldPetInfo            TextField = "pet.info"
+	FieldServiceTitle       TextField = "service.title"
+	FieldServiceDescription TextField = "service.description"
+)
+
+// Name returns the name of the field in API requests.
+func (f TextField) Name() string {
+	_, name, _ := strings.Cut(string(f), ".")
+	return name
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type NotificationKind string
+
+const (
+	NotificationImageApproved NotificationKind = "image_approved"
+	NotificationImageRejected NotificationKind = "image_rejected"
+	NotificationTextFlagged   NotificationKind = "text_flagged"
+	NotificationContentHidden NotificationKind = "content_hidden"
+	NotificationWarning       NotificationKind = "moderation_warning"
+	NotificationSuspended     NotificationKind = "account_suspended"
+	NotificationSearchMatch   NotificationKind = "saved_search_match"
+)
+
+type ApiNotification struct {
+	NotificationID string           `json:"notification_id"`
+	Kind           NotificationKind `json:"kind"`
+	Message        string           `json:"message"`
+	SubjectID      string           `json:"subject_id,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Read           bool             `json:"read"`
+}
+
+type DBNotification struct {
+	NotificationID bson.ObjectID    `bson:"_id,omitempty"`
+	UserID         bson.ObjectID    `bson:"user"`
+	Kind           NotificationKind `bson:"kind"`
+	Message        string           `bson:"message"`
+	SubjectID      string           `bson:"subject_id,omitempty"`
+	CreatedAt      time.Time        `bson:"created_at"`
+	Read           bool             `bson:"read"`
+}
+
+func (db *DBNotification) ToApi() *ApiNotification {
+	return &ApiNotification{
+		NotificationID: db.NotificationID.Hex(),
+		Kind:           db.Kind,
+		Message:        db.Message,
+		SubjectID:      db.SubjectID,
+		CreatedAt:      db.CreatedAt,
+		Read:           db.Read,
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"encoding/base64"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ImageTarget string
+
+const (
+	TargetAvatar       ImageTarget = "avatar"
+	TargetBackground   ImageTarget = "background"
+	TargetPetAvatar    ImageTarget = "pet_avatar"
+	TargetServicePhoto ImageTarget = "service_photo"
+)
+
+type ModerationStatus string
+
+const (
+	StatusPending  ModerationStatus = "pending"
+	StatusReview   ModerationStatus = "review"
+	StatusFlagged  ModerationStatus = "flagged"
+	StatusApproved ModerationStatus = "approved"
+	StatusRejected ModerationStatus = "rejected"
+)
+
+type ImageUpload struct {
+	ImageID  string
+	Target   ImageTarget
+	TargetID string
+	Image    string
+}
+
+type ApiPendingImage struct {
+	ImageID         string           `json:"image_id"`
+	OwnerID         string           `json:"owner_id"`
+	Target          ImageTarget      `json:"target"`
+	TargetID        string           `json:"target_id"`
+	Image           string           `json:"image,omitempty"`
+	Status          ModerationStatus `json:"status"`
+	NSFWProbability float64          `json:"nsfw_probability"`
+	CreatedAt       time.Time        `json:"created_at"`
+}
+
+type DBPendingImage struct {
+	ImageID         bson.ObjectID    `bson:"_id,omitempty"`
+	OwnerID         bson.ObjectID    `bson:"owner"`
+	Target          ImageTarget      `bson:"target"`
+	TargetID        bson.ObjectID    `bson:"target_id"`
+	Image           []byte           `bson:"image"`
+	Status          ModerationStatus `bson:"status"`
+	NSFWProbability float64          `bson:"nsfw_probability"`
+	CreatedAt       time.Time        `bson:"created_at"`
+	ReviewedBy      bson.ObjectID    `bson:"reviewed_by,omitempty"`
+	ReviewedAt      time.Time        `bson:"reviewed_at,omitempty"`
+}
+
+func (db *DBPendingImage) ToApi() *ApiPendingImage {
+	apiImage := &ApiPendingImage{
+		ImageID:         db.ImageID.Hex(),
+		OwnerID:         db.OwnerID.Hex(),
+		Target:          db.Target,
+		TargetID:        db.TargetID.Hex(),
+		Status:          db.Status,
+		NSFWProbability: db.NSFWProbability,
+		CreatedAt:       db.CreatedAt,
+	}
+
+	if len(db.Image) != 0 {
+		apiImage.Image = base64.StdEncoding.EncodeToString(db.Image)
+	}
+
+	return apiImage
+}
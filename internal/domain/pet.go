@@ -9,6 +9,7 @@ import (
 type ApiPetInfo struct {
 	PetID        string `json:"pet_id,omitempty"`
 	TypeOfAnimal string `json:"type_of_animal,omitempty"`
+	AnimalID     string `json:"animal_id,omitempty"`
 	Name         string `json:"name,omitempty"`
 	Info         string `json:"info,omitempty"`
 	PetAvatar    string `json:"avatar"`
@@ -17,6 +18,7 @@ type ApiPetInfo struct {
 type DBPetInfo struct {
 	PetID        bson.ObjectID `bson:"_id,omitempty"`
 	TypeOfAnimal string        `bson:"type,omitempty"`
+	AnimalID     string        `bson:"animal_id,omitempty"`
 	Name         string        `bson:"name,omitempty"`
 	Info         string        `bson:"info,omitempty"`
 	PetAvatar    []byte        `bson:"avatar_url,omitempty"`
@@ -54,6 +56,7 @@ func (dbInfo *DBPetInfo) ToApi() *ApiPetInfo {
 	apiInfo := &ApiPetInfo{
 		PetID:        dbInfo.PetID.Hex(),
 		TypeOfAnimal: dbInfo.TypeOfAnimal,
+		AnimalID:     dbInfo.AnimalID,
 		Name:         dbInfo.Name,
 		Info:         dbInfo.Info,
 	}
@@ -78,6 +81,7 @@ type ApiPetUpdate struct {
 
 type DBPetUpdate struct {
 	TypeOfAnimal string `bson:"type,omitempty"`
+	AnimalID     string `bson:"animal_id,omitempty"`
 	Name         string `bson:"name,omitempty"`
 	Info         string `bson:"info,omitempty"`
 	PetAvatar    []byte `bson:"avatar_url,omitempty"`
@@ -108,7 +112,8 @@ type PetAdviceRequest struct {
 }
 
 type PetAdviceResponse struct {
-	Advice string `json:"advice"`
-	Animal string `json:"animal"`
-	Prompt string `json:"prompt"`
+	Advice   string `json:"advice"`
+	Animal   string `json:"animal"`
+	Prompt   string `json:"prompt"`
+	Degraded bool   `json:"degraded,omitempty"`
 }
@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ReportTarget string
+
+const (
+	ReportTargetUser    ReportTarget = "user"
+	ReportTargetPet     ReportTarget = "pet"
+	ReportTargetService ReportTarget = "service"
+)
+
+func IsReportTarget(target ReportTarget) bool {
+	return target == ReportTargetUser || target == ReportTargetPet || target == ReportTargetService
+}
+
+type ReportReason string
+
+const (
+	ReasonScam          ReportReason = "scam"
+	ReasonAbuse         ReportReason = "abuse"
+	ReasonInappropriate ReportReason = "inappropriate_content"
+	ReasonSpam          ReportReason = "spam"
+	ReasonOther         ReportReason = "other"
+)
+
+func IsReportReason(reason ReportReason) bool {
+	return reason == ReasonScam || reason == ReasonAbuse || reason == ReasonInappropriate ||
+		reason == ReasonSpam || reason == ReasonOther
+}
+
+type CaseStatus string
+
+const (
+	CaseOpen      CaseStatus = "open"
+	CaseResolved  CaseStatus = "resolved"
+	CaseDismissed CaseStatus = "dismissed"
+)
+
+type CaseAction string
+
+const (
+	// ActionHideContent hides the reported user, pet or service.
+	ActionHideContent CaseAction = "hide"
+	// ActionUnhideContent brings hidden content back, e.g. after an automatic
+	// hide turned out to be wrong.
+	ActionUnhideContent CaseAction = "unhide"
+	// ActionWarnUser notifies the owner of the content.
+	ActionWarnUser CaseAction = "warn"
+	// ActionSuspendUser keeps the owner from logging in and posting.
+	ActionSuspendUser CaseAction = "suspend"
+	// ActionDismissCase closes the case without measures.
+	ActionDismissCase CaseAction = "dismiss"
+)
+
+func IsCaseAction(action CaseAction) bool {
+	return action == ActionHideContent || action == ActionUnhideContent || action == ActionWarnUser ||
+		action == ActionSuspendUser || action == ActionDismissCase
+}
+
+type ApiReport struct {
+	Target   ReportTarget `json:"target"`
+	TargetID string       `json:"target_id"`
+	Reason   ReportReason `json:"reason"`
+	Comment  string       `json:"comment,omitempty"`
+}
+
+type ApiCaseReport struct {
+	ReporterID string       `json:"reporter_id"`
+	Reason     ReportReason `json:"reason"`
+	Comment    string       `json:"comment,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type DBCaseReport struct {
+	ReporterID bson.ObjectID `bson:"reporter"`
+	Reason     ReportReason  `bson:"reason"`
+	Comment    string        `bson:"comment,omitempty"`
+	CreatedAt  time.Time     `bson:"created_at"`
+}
+
+func (db *DBCaseReport) ToApi() *ApiCaseReport {
+	return &ApiCaseReport{
+		ReporterID: db.ReporterID.Hex(),
+		Reason:     db.Reason,
+		Comment:    db.Comment,
+		CreatedAt:  db.CreatedAt,
+	}
+}
+
+// ApiCaseActionRequest is a moderator decision on a case. SuspendDays is
+// used by the suspend action only.
+type ApiCaseActionRequest struct {
+	CaseID      string     `json:"case_id"`
+	Action      CaseAction `json:"action"`
+	Comment     string     `json:"comment,omitempty"`
+	SuspendDays int        `json:"suspend_days,omitempty"`
+}
+
+// ApiCaseActionEntry is a record of the audit trail of a case. Automatic
+// actions have no moderator.
+type ApiCaseActionEntry struct {
+	ModeratorID    string     `json:"moderator_id,omitempty"`
+	Action         CaseAction `json:"action"`
+	Comment        string     `json:"comment,omitempty"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	Automatic      bool       `json:"automatic"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type DBCaseActionEntry struct {
+	ModeratorID    bson.ObjectID `bson:"moderator,omitempty"`
+	Action         CaseAction    `bson:"action"`
+	Comment        string        `bson:"comment,omitempty"`
+	SuspendedUntil *time.Time    `bson:"suspended_until,omitempty"`
+	Automatic      bool          `bson:"automatic"`
+	CreatedAt      time.Time     `bson:"created_at"`
+}
+
+func (db *DBCaseActionEntry) ToApi() *ApiCaseActionEntry {
+	api := &ApiCaseActionEntry{
+		Action:         db.Action,
+		Comment:        db.Comment,
+		SuspendedUntil: db.SuspendedUntil,
+		Automatic:      db.Automatic,
+		CreatedAt:      db.CreatedAt,
+	}
+
+	if !db.ModeratorID.IsZero() {
+		api.ModeratorID = db.ModeratorID.Hex()
+	}
+
+	return api
+}
+
+// ApiModerationCase gathers the reports on one piece of content until a
+// moderator resolves it; later reports open a new case.
+type ApiModerationCase struct {
+	CaseID      string                `json:"case_id"`
+	Target      ReportTarget          `json:"target"`
+	TargetID    string                `json:"target_id"`
+	OwnerID     string                `json:"owner_id,omitempty"`
+	Status      CaseStatus            `json:"status"`
+	ReportCount int                   `json:"report_count"`
+	Reasons     map[ReportReason]int  `json:"reasons"`
+	Hidden      bool                  `json:"hidden"`
+	Reports     []*ApiCaseReport      `json:"reports,omitempty"`
+	Actions     []*ApiCaseActionEntry `json:"actions,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+type DBModerationCase struct {
+	CaseID      bson.ObjectID        `bson:"_id,omitempty"`
+	Target      ReportTarget         `bson:"target"`
+	TargetID    bson.ObjectID        `bson:"target_id"`
+	OwnerID     bson.ObjectID        `bson:"owner,omitempty"`
+	Status      CaseStatus           `bson:"status"`
+	ReportCount int                  `bson:"report_count"`
+	Reasons     map[ReportReason]int `bson:"reasons"`
+	Hidden      bool                 `bson:"hidden"`
+	Reports     []DBCaseReport       `bson:"reports"`
+	Actions     []DBCaseActionEntry  `bson:"actions,omitempty"`
+	CreatedAt   time.Time            `bson:"created_at"`
+	UpdatedAt   time.Time            `bson:"updated_at"`
+}
+
+// ToApi converts the case leaving out the reports and actions unless full
+// is set, since case lists only need the summary.
+func (db *DBModerationCase) ToApi(full bool) *ApiModerationCase {
+	api := &ApiModerationCase{
+		CaseID:      db.CaseID.Hex(),
+		Target:      db.Target,
+		TargetID:    db.TargetID.Hex(),
+		Status:      db.Status,
+		ReportCount: db.ReportCount,
+		Reasons:     db.Reasons,
+		Hidden:      db.Hidden,
+		CreatedAt:   db.CreatedAt,
+		UpdatedAt:   db.UpdatedAt,
+	}
+
+	if !db.OwnerID.IsZero() {
+		api.OwnerID = db.OwnerID.Hex()
+	}
+
+	if api.Reasons == nil {
+		api.Reasons = map[ReportReason]int{}
+	}
+
+	if full {
+		api.Reports = make([]*ApiCaseReport, len(db.Reports))
+		for i := range db.Reports {
+			api.Reports[i] = db.Reports[i].ToApi()
+		}
+
+		api.Actions = make([]*ApiCaseActionEntry, len(db.Actions))
+		for i := range db.Actions {
+			api.Actions[i] = db.Actions[i].ToApi()
+		}
+	}
+
+	return api
+}
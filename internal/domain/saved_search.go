@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ApiSavedSearch is a search the user is alerted about when a new service
+// matches it. Of the filter only the prices and the animals are kept.
+type ApiSavedSearch struct {
+	SavedSearchID string         `json:"saved_search_id,omitempty"`
+	UserID        string         `json:"user_id,omitempty"`
+	Query         string         `json:"query,omitempty"`
+	Filter        *ServiceFilter `json:"filter,omitempty"`
+	// Email gets digests of the matches besides the notifications, once
+	// the address is confirmed.
+	Email          string    `json:"email,omitempty"`
+	EmailConfirmed bool      `json:"email_confirmed"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type DBSavedSearch struct {
+	SavedSearchID bson.ObjectID `bson:"_id,omitempty"`
+	UserID        bson.ObjectID `bson:"user"`
+	Query         string        `bson:"query"`
+	MinPrice      int32         `bson:"min_price"`
+	MaxPrice      int32         `bson:"max_price"`
+	Animals       []string      `bson:"animals"`
+	Email         string        `bson:"email,omitempty"`
+	// EmailToken is sent to the address and dropped once it is confirmed.
+	EmailToken     string    `bson:"email_token,omitempty"`
+	EmailConfirmed bool      `bson:"email_confirmed"`
+	CreatedAt      time.Time `bson:"created_at"`
+}
+
+func (db *DBSavedSearch) ToApi() *ApiSavedSearch {
+	return &ApiSavedSearch{
+		SavedSearchID: db.SavedSearchID.Hex(),
+		UserID:        db.UserID.Hex(),
+		Query:         db.Query,
+		Filter: &ServiceFilter{
+			MinPrice: db.MinPrice,
+			MaxPrice: db.MaxPrice,
+			Animals:  db.Animals,
+		},
+		Email:          db.Email,
+		EmailConfirmed: db.EmailConfirmed,
+		CreatedAt:      db.CreatedAt,
+	}
+}
+
+// DBSearchAlert is a match of a saved search waiting to be sent by e-mail in
+// the next digest. Sent alerts are deleted.
+type DBSearchAlert struct {
+	AlertID       bson.ObjectID `bson:"_id,omitempty"`
+	SavedSearchID bson.ObjectID `bson:"saved_search"`
+	Email         string        `bson:"email"`
+	Query         string        `bson:"query"`
+	ServiceID     bson.ObjectID `bson:"service"`
+	Title         string        `bson:"title"`
+	Price         int32         `bson:"price"`
+	CreatedAt     time.Time     `bson:"created_at"`
+}
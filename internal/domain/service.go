@@ -18,26 +18,101 @@ func IsRole(str Role) bool {
 	return str == Provider || str == Customer
 }
 
+type ApiServicePhoto struct {
+	PhotoID string `json:"photo_id,omitempty"`
+	Image   string `json:"image,omitempty"`
+}
+
+type DBServicePhoto struct {
+	PhotoID bson.ObjectID `bson:"_id"`
+	Image   []byte        `bson:"image"`
+}
+
+func (api *ApiServicePhoto) ToDB() (*DBServicePhoto, error) {
+	dbPhoto := &DBServicePhoto{}
+
+	if api.PhotoID != "" {
+		photoID, err := bson.ObjectIDFromHex(api.PhotoID)
+		if err != nil {
+			return nil, err
+		}
+
+		dbPhoto.PhotoID = photoID
+	}
+
+	if api.Image != "" {
+		byteImage, err := base64.StdEncoding.DecodeString(api.Image)
+		if err != nil {
+			return nil, err
+		}
+
+		dbPhoto.Image = byteImage
+	}
+
+	return dbPhoto, nil
+}
+
+func (db *DBServicePhoto) ToApi() *ApiServicePhoto {
+	apiPhoto := &ApiServicePhoto{
+		PhotoID: db.PhotoID.Hex(),
+	}
+
+	if len(db.Image) != 0 {
+		apiPhoto.Image = base64.StdEncoding.EncodeToString(db.Image)
+	}
+
+	return apiPhoto
+}
+
+type ServicePhotoOrder struct {
+	PhotoIDs []string `json:"photo_ids"`
+}
+
 type ApiService struct {
-	ServiceID   string   `json:"service_id,omitempty"`
-	Type        Role     `json:"role,omitempty"`
-	UserID      string   `json:"user_id,omitempty"`
-	Title       string   `json:"title,omitempty"`
-	Price       int32    `json:"price"`
-	Description string   `json:"description,omitempty"`
-	UserImage   string   `json:"user_image"`
-	PetIDs      []string `json:"pet_ids"`
+	ServiceID     string             `json:"service_id,omitempty"`
+	Type          Role               `json:"role,omitempty"`
+	UserID        string             `json:"user_id,omitempty"`
+	Title         string             `json:"title,omitempty"`
+	Price         int32              `json:"price"`
+	Description   string             `json:"description,omitempty"`
+	UserImage     string             `json:"user_image"`
+	PetIDs        []string           `json:"pet_ids"`
+	Photos        []*ApiServicePhoto `json:"photos,omitempty"`
+	CoverPhotoID  string             `json:"cover_photo_id,omitempty"`
+	CoverPhoto    string             `json:"cover_photo,omitempty"`
+	Highlight     *ServiceHighlight  `json:"highlight,omitempty"`
+	FavoriteCount int64              `json:"favorite_count"`
+	IsFavorite    bool               `json:"is_favorite,omitempty"`
+}
+
+// ServiceHighlight is the HTML escaped title and description snippet of a
+// found service with the words matching the query wrapped into <mark>.
+type ServiceHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+type SimilarService struct {
+	Service *ApiService `json:"service"`
+	Score   float64     `json:"score"`
 }
 
 type DBService struct {
-	ServiceID   bson.ObjectID `bson:"_id,omitempty"`
-	Type        Role          `bson:"role,omitempty"`
-	UserID      bson.M        `bson:"owner,omitempty"`
-	Title       string        `bson:"title"`
-	Price       int32         `bson:"price,omitempty"`
-	Description string        `bson:"description,omitempty"`
-	UserImage   []byte        `bson:"user_image,omitempty"`
-	PetIDs      []bson.M      `bson:"pets,omitempty"`
+	ServiceID     bson.ObjectID    `bson:"_id,omitempty"`
+	Type          Role             `bson:"role,omitempty"`
+	UserID        bson.M           `bson:"owner,omitempty"`
+	Title         string           `bson:"title"`
+	Price         int32            `bson:"price,omitempty"`
+	Description   string           `bson:"description,omitempty"`
+	UserImage     []byte           `bson:"user_image,omitempty"`
+	PetIDs        []bson.M         `bson:"pets,omitempty"`
+	Animals       []string         `bson:"animals"`
+	TitleWords    []string         `bson:"title_words"`
+	TitleTrigrams []string         `bson:"title_trigrams"`
+	Language      string           `bson:"language,omitempty"`
+	Photos        []DBServicePhoto `bson:"photos,omitempty"`
+	CoverPhotoID  bson.ObjectID    `bson:"cover_photo,omitempty"`
+	FavoriteCount int64            `bson:"favorite_count,omitempty"`
 }
 
 func (api *ApiService) ToDB() (*DBService, error) {
@@ -104,11 +179,12 @@ func (api *ApiService) ToDB() (*DBService, error) {
 
 func (db *DBService) ToApi() (*ApiService, error) {
 	apiServ := &ApiService{
-		ServiceID:   db.ServiceID.Hex(),
-		Type:        db.Type,
-		Title:       db.Title,
-		Description: db.Description,
-		Price:       db.Price,
+		ServiceID:     db.ServiceID.Hex(),
+		Type:          db.Type,
+		Title:         db.Title,
+		Description:   db.Description,
+		Price:         db.Price,
+		FavoriteCount: db.FavoriteCount,
 	}
 
 	if db.UserID != nil {
@@ -137,38 +213,119 @@ func (db *DBService) ToApi() (*ApiService, error) {
 		apiServ.UserImage = base64.StdEncoding.EncodeToString(db.UserImage)
 	}
 
+	if len(db.Photos) > 0 {
+		apiPhotos := make([]*ApiServicePhoto, len(db.Photos))
+		for i, photo := range db.Photos {
+			apiPhotos[i] = photo.ToApi()
+		}
+
+		apiServ.Photos = apiPhotos
+
+		cover := apiPhotos[0]
+		for _, photo := range apiPhotos {
+			if photo.PhotoID == db.CoverPhotoID.Hex() {
+				cover = photo
+				break
+			}
+		}
+
+		apiServ.CoverPhotoID = cover.PhotoID
+		apiServ.CoverPhoto = cover.Image
+	}
+
 	return apiServ, nil
 }
 
 type DBServiceSerachResult struct {
-	ServiceID   bson.ObjectID `bson:"_id,omitempty"`
-	Type        Role          `bson:"role,omitempty"`
-	UserID      bson.M        `bson:"owner,omitempty"`
-	Title       string        `bson:"title"`
-	Price       int32         `bson:"price,omitempty"`
-	Description string        `bson:"description,omitempty"`
-	Score       float64       `bson:"score,omitempty"`
-	UserImage   []byte        `bson:"user_image,omitempty"`
-	PetIDs      []bson.M      `bson:"pets,omitempty"`
+	ServiceID     bson.ObjectID    `bson:"_id,omitempty"`
+	Type          Role             `bson:"role,omitempty"`
+	UserID        bson.M           `bson:"owner,omitempty"`
+	Title         string           `bson:"title"`
+	Price         int32            `bson:"price,omitempty"`
+	Description   string           `bson:"description,omitempty"`
+	Score         float64          `bson:"score,omitempty"`
+	UserImage     []byte           `bson:"user_image,omitempty"`
+	PetIDs        []bson.M         `bson:"pets,omitempty"`
+	Photos        []DBServicePhoto `bson:"photos,omitempty"`
+	CoverPhotoID  bson.ObjectID    `bson:"cover_photo,omitempty"`
+	FavoriteCount int64            `bson:"favorite_count,omitempty"`
 }
 
 func (db *DBServiceSerachResult) ToApiService() (*ApiService, error) {
 	dbService := &DBService{
-		ServiceID:   db.ServiceID,
-		Type:        db.Type,
-		UserID:      db.UserID,
-		Title:       db.Title,
-		Description: db.Description,
-		Price:       db.Price,
-		UserImage:   db.UserImage,
-		PetIDs:      db.PetIDs,
+		ServiceID:     db.ServiceID,
+		Type:          db.Type,
+		UserID:        db.UserID,
+		Title:         db.Title,
+		Description:   db.Description,
+		Price:         db.Price,
+		UserImage:     db.UserImage,
+		PetIDs:        db.PetIDs,
+		Photos:        db.Photos,
+		CoverPhotoID:  db.CoverPhotoID,
+		FavoriteCount: db.FavoriteCount,
 	}
 
 	return dbService.ToApi()
 }
 
+type SearchSort string
+
+const (
+	// SortRelevance orders by text score, falling back to SortNewest when
+	// there is no search string.
+	SortRelevance SearchSort = "relevance"
+	SortNewest    SearchSort = "newest"
+	SortPriceAsc  SearchSort = "price_asc"
+	SortPriceDesc SearchSort = "price_desc"
+)
+
+func IsSearchSort(sort SearchSort) bool {
+	return sort == SortRelevance || sort == SortNewest || sort == SortPriceAsc || sort == SortPriceDesc
+}
+
 type ServiceFilter struct {
-	MinPrice int32    `json:"min_price,omitempty"`
-	MaxPrice int32    `json:"max_price,omitempty"`
-	Animals  []string `json:"animals,omitempty"`
+	MinPrice int32      `json:"min_price,omitempty"`
+	MaxPrice int32      `json:"max_price,omitempty"`
+	Animals  []string   `json:"animals,omitempty"`
+	Sort     SearchSort `json:"sort,omitempty"`
+	Page     int64      `json:"page,omitempty"`
+	PageSize int64      `json:"page_size,omitempty"`
+	Facets   bool       `json:"facets,omitempty"`
+	// Language of the search string, detected when not given.
+	Language string `json:"language,omitempty"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// PriceBucket counts the prices from Min up to Max, not included; the last
+// bucket has no Max.
+type PriceBucket struct {
+	Min   int32  `json:"min"`
+	Max   *int32 `json:"max,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// SearchFacets are counted over all the services matching the search, not
+// only the returned page. Ratings are counted by whole stars.
+type SearchFacets struct {
+	Total   int64          `json:"total"`
+	Animals []*FacetCount  `json:"animals"`
+	Roles   []*FacetCount  `json:"roles"`
+	Prices  []*PriceBucket `json:"prices"`
+	Ratings []*FacetCount  `json:"ratings"`
+}
+
+// Suggestions complete what the user is typing in the search field.
+type Suggestions struct {
+	Titles  []string `json:"titles"`
+	Animals []string `json:"animals"`
+}
+
+type ServiceSearchResult struct {
+	Services []*ApiService `json:"services"`
+	Facets   *SearchFacets `json:"facets,omitempty"`
 }
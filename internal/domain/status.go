@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+type IntegrationStatus struct {
+	Name                string     `json:"name"`
+	State               string     `json:"state"`
+	FailMode            string     `json:"fail_mode"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ApiSwearWord struct {
+	Word     string `json:"word"`
+	Language string `json:"language,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	Allowed  bool   `json:"allowed,omitempty"`
+}
+
+type DBSwearWord struct {
+	Word        string        `bson:"word"`
+	Language    string        `bson:"language,omitempty"`
+	Severity    string        `bson:"severity,omitempty"`
+	Allowed     bool          `bson:"allowed"`
+	ModeratorID bson.ObjectID `bson:"moderator"`
+	UpdatedAt   time.Time     `bson:"updated_at"`
+}
+
+func (db *DBSwearWord) ToApi() *ApiSwearWord {
+	return &ApiSwearWord{
+		Word:     db.Word,
+		Language: db.Language,
+		Severity: db.Severity,
+		Allowed:  db.Allowed,
+	}
+}
@@ -0,0 +1,227 @@
+package mongoTLC
+
+import (
+	"context"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// AnimalMergeResult counts what MergeAnimalVariants changed.
+type AnimalMergeResult struct {
+	Pets          int
+	MergedAnimals int
+	SavedSearches int
+}
+
+// MergeAnimalVariants moves the data stored before the animal taxonomy onto
+// its IDs: pets get the ID of their animal type, the rows of the animal
+// collection spelling the same animal differently ("кот", "кошка", "cats")
+// are merged into one, and the animals of services and saved searches are
+// rewritten with the IDs.
+func MergeAnimalVariants(db *mongo.Database) (*AnimalMergeResult, error) {
+	result := new(AnimalMergeResult)
+
+	var err error
+	result.Pets, err = backfillPetAnimalIDs(db)
+	if err != nil {
+		return nil, err
+	}
+
+	result.MergedAnimals, err = mergeAnimalRows(db)
+	if err != nil {
+		return nil, err
+	}
+
+	err = syncServiceAnimals(db, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	result.SavedSearches, err = normalizeSavedSearchAnimals(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func backfillPetAnimalIDs(db *mongo.Database) (int, error) {
+	opt := options.Find().SetProjection(bson.M{"type": 1, "animal_id": 1})
+	cursor, err := db.Collection("pet").Find(context.TODO(), bson.M{"type": bson.M{"$exists": true}}, opt)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	models := []mongo.WriteModel{}
+	for cursor.Next(context.TODO()) {
+		var pet struct {
+			PetID        bson.ObjectID `bson:"_id"`
+			TypeOfAnimal string        `bson:"type"`
+			AnimalID     string        `bson:"animal_id"`
+		}
+
+		if err = cursor.Decode(&pet); err != nil {
+			return 0, err
+		}
+
+		animalID := CanonicalAnimal(pet.TypeOfAnimal)
+		if animalID == "" || animalID == pet.AnimalID {
+			continue
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": pet.PetID}).
+			SetUpdate(bson.M{"$set": bson.M{"animal_id": animalID}}))
+	}
+
+	if err = cursor.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	_, err = db.Collection("pet").BulkWrite(context.TODO(), models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(models), nil
+}
+
+// mergeAnimalRows replaces the rows of every animal spelled in more than one
+// way, or not by its ID, with a single row holding the services of them all.
+// Rows count pets, a service being listed once for every pet it has of the
+// animal, since services are decremented pet by pet when deleted. It returns
+// the number of rows removed.
+func mergeAnimalRows(db *mongo.Database) (int, error) {
+	animalColl := db.Collection("animal")
+
+	cursor, err := animalColl.Find(context.TODO(), bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var rows []struct {
+		RowID        bson.ObjectID `bson:"_id"`
+		TypeOfAnimal string        `bson:"type_of_animal"`
+		Services     []bson.M      `bson:"services"`
+	}
+	if err = cursor.All(context.TODO(), &rows); err != nil {
+		return 0, err
+	}
+
+	type mergedRow struct {
+		rowIDs   []bson.ObjectID
+		exact    bool
+		services []bson.M
+	}
+
+	order := []string{}
+	merged := map[string]*mergedRow{}
+	for _, row := range rows {
+		animal := NormalizeAnimal(row.TypeOfAnimal)
+		if animal == "" {
+			continue
+		}
+
+		group, found := merged[animal]
+		if !found {
+			group = &mergedRow{services: []bson.M{}}
+			merged[animal] = group
+			order = append(order, animal)
+		}
+
+		group.rowIDs = append(group.rowIDs, row.RowID)
+		group.exact = group.exact || row.TypeOfAnimal == animal
+		group.services = append(group.services, row.Services...)
+	}
+
+	removed := 0
+	models := []mongo.WriteModel{}
+	for _, animal := range order {
+		group := merged[animal]
+		if len(group.rowIDs) == 1 && group.exact {
+			continue
+		}
+
+		models = append(models,
+			mongo.NewDeleteManyModel().SetFilter(bson.M{"_id": bson.M{"$in": group.rowIDs}}),
+			mongo.NewInsertOneModel().SetDocument(bson.M{
+				"type_of_animal": animal,
+				"count":          int32(len(group.services)),
+				"services":       group.services,
+			}),
+		)
+		removed += len(group.rowIDs) - 1
+	}
+
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	// each group is deleted before its merged row is inserted
+	_, err = animalColl.BulkWrite(context.TODO(), models, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
+
+func normalizeSavedSearchAnimals(db *mongo.Database) (int, error) {
+	opt := options.Find().SetProjection(bson.M{"animals": 1})
+	cursor, err := db.Collection("saved_search").Find(context.TODO(), bson.M{"animals.0": bson.M{"$exists": true}}, opt)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	models := []mongo.WriteModel{}
+	for cursor.Next(context.TODO()) {
+		var search struct {
+			SavedSearchID bson.ObjectID `bson:"_id"`
+			Animals       []string      `bson:"animals"`
+		}
+
+		if err = cursor.Decode(&search); err != nil {
+			return 0, err
+		}
+
+		animals := []string{}
+		for _, animal := range search.Animals {
+			if animal = NormalizeAnimal(animal); animal != "" && !slices.Contains(animals, animal) {
+				animals = append(animals, animal)
+			}
+		}
+
+		if slices.Equal(animals, search.Animals) {
+			continue
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": search.SavedSearchID}).
+			SetUpdate(bson.M{"$set": bson.M{"animals": animals}}))
+	}
+
+	if err = cursor.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	_, err = db.Collection("saved_search").BulkWrite(context.TODO(), models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(models), nil
+}
@@ -6,9 +6,19 @@ var (
 	BAD_USER_ID           = fmt.Errorf("bad user ID")
 	BAD_PET_ID            = fmt.Errorf("bad pet ID")
 	BAD_SERVICE_ID        = fmt.Errorf("bad_service_id")
+	BAD_PHOTO_ID          = fmt.Errorf("bad photo ID")
+	BAD_IMAGE_ID          = fmt.Errorf("bad image ID")
+	BAD_TARGET_ID         = fmt.Errorf("bad ID of the entity the image belongs to")
 	NOT_FOUND             = fmt.Errorf("no data found")
 	EMPTY_LOGIN           = fmt.Errorf("login must be non-empty")
 	LOGIN_EXISTS          = fmt.Errorf("specified login already exists")
 	INCORRECT_CREDENTIALS = fmt.Errorf("incorrect credentials")
 	ACCESS_DENIED         = fmt.Errorf("you have no access to this resource")
+	TOO_MANY_PHOTOS       = fmt.Errorf("the service already has the maximum number of photos")
+	PHOTO_ORDER_MISMATCH  = fmt.Errorf("new photo order must list every photo of the service exactly once")
+	NOT_UNDER_REVIEW      = fmt.Errorf("the image is not waiting for a moderator review")
+	UNKNOWN_IMAGE_TARGET  = fmt.Errorf("unknown kind of image")
+	BAD_CASE_ID           = fmt.Errorf("bad moderation case ID")
+	ALREADY_REPORTED      = fmt.Errorf("you have already reported this content")
+	BAD_SAVED_SEARCH_ID   = fmt.Errorf("bad saved search ID")
 )
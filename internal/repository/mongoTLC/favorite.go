@@ -0,0 +1,184 @@
+package mongoTLC
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"mainService/internal/domain"
+)
+
+type IFavoriteRepository interface {
+	AddFavorite(userID string, favorite *domain.ApiFavorite) error
+	DeleteFavorite(userID string, favorite *domain.ApiFavorite) error
+	GetFavoriteIDs(userID string, target domain.FavoriteTarget) ([]string, error)
+	GetFavoriteSet(userID string, target domain.FavoriteTarget, targetIDs []string) (map[string]struct{}, error)
+	DeleteTargetFavorites(target domain.FavoriteTarget, targetID string) error
+}
+
+type mongoFavoriteRepository struct {
+	DB          *mongo.Database
+	Coll        *mongo.Collection
+	ServiceColl *mongo.Collection
+}
+
+func NewMongoFavoriteRepository(db *mongo.Database) IFavoriteRepository {
+	return &mongoFavoriteRepository{
+		DB:          db,
+		Coll:        db.Collection("favorite"),
+		ServiceColl: db.Collection("service"),
+	}
+}
+
+func favoriteFilter(userID string, favorite *domain.ApiFavorite) (bson.M, bson.ObjectID, error) {
+	userMongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, bson.ObjectID{}, BAD_USER_ID
+	}
+
+	targetMongoID, err := bson.ObjectIDFromHex(favorite.TargetID)
+	if err != nil {
+		return nil, bson.ObjectID{}, BAD_TARGET_ID
+	}
+
+	filter := bson.M{
+		"user":      userMongoID,
+		"target":    favorite.Target,
+		"target_id": targetMongoID,
+	}
+
+	return filter, targetMongoID, nil
+}
+
+// AddFavorite does nothing for a target already in favorites, so the
+// favorites count of a service grows only once per user.
+func (repo *mongoFavoriteRepository) AddFavorite(userID string, favorite *domain.ApiFavorite) error {
+	filter, targetMongoID, err := favoriteFilter(userID, favorite)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$setOnInsert": bson.M{"created_at": time.Now()},
+	}
+
+	res, err := repo.Coll.UpdateOne(context.TODO(), filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if res.UpsertedCount == 0 || favorite.Target != domain.FavoriteService {
+		return nil
+	}
+
+	_, err = repo.ServiceColl.UpdateByID(context.TODO(), targetMongoID, bson.M{"$inc": bson.M{"favorite_count": 1}})
+	return err
+}
+
+func (repo *mongoFavoriteRepository) DeleteFavorite(userID string, favorite *domain.ApiFavorite) error {
+	filter, targetMongoID, err := favoriteFilter(userID, favorite)
+	if err != nil {
+		return err
+	}
+
+	res, err := repo.Coll.DeleteOne(context.TODO(), filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return NOT_FOUND
+	}
+
+	if favorite.Target != domain.FavoriteService {
+		return nil
+	}
+
+	_, err = repo.ServiceColl.UpdateByID(context.TODO(), targetMongoID, bson.M{"$inc": bson.M{"favorite_count": -1}})
+	return err
+}
+
+// GetFavoriteIDs returns the IDs of the user's favorites, the latest added
+// first.
+func (repo *mongoFavoriteRepository) GetFavoriteIDs(userID string, target domain.FavoriteTarget) ([]string, error) {
+	mongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, BAD_USER_ID
+	}
+
+	opt := options.Find().
+		SetSort(bson.D{{"created_at", -1}}).
+		SetProjection(bson.M{"target_id": 1})
+
+	cursor, err := repo.Coll.Find(context.TODO(), bson.M{"user": mongoID, "target": target}, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var favorites []*domain.DBFavorite
+	if err = cursor.All(context.TODO(), &favorites); err != nil {
+		return nil, err
+	}
+
+	targetIDs := make([]string, len(favorites))
+	for i, favorite := range favorites {
+		targetIDs[i] = favorite.TargetID.Hex()
+	}
+
+	return targetIDs, nil
+}
+
+// GetFavoriteSet tells which of the targets are in the user's favorites.
+func (repo *mongoFavoriteRepository) GetFavoriteSet(userID string, target domain.FavoriteTarget, targetIDs []string) (map[string]struct{}, error) {
+	mongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, BAD_USER_ID
+	}
+
+	targetMongoIDs := make([]bson.ObjectID, 0, len(targetIDs))
+	for _, id := range targetIDs {
+		if targetMongoID, err := bson.ObjectIDFromHex(id); err == nil {
+			targetMongoIDs = append(targetMongoIDs, targetMongoID)
+		}
+	}
+
+	filter := bson.M{
+		"user":      mongoID,
+		"target":    target,
+		"target_id": bson.M{"$in": targetMongoIDs},
+	}
+
+	cursor, err := repo.Coll.Find(context.TODO(), filter, options.Find().SetProjection(bson.M{"target_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var favorites []*domain.DBFavorite
+	if err = cursor.All(context.TODO(), &favorites); err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]struct{}, len(favorites))
+	for _, favorite := range favorites {
+		set[favorite.TargetID.Hex()] = struct{}{}
+	}
+
+	return set, nil
+}
+
+// DeleteTargetFavorites removes a deleted target from everybody's favorites.
+func (repo *mongoFavoriteRepository) DeleteTargetFavorites(target domain.FavoriteTarget, targetID string) error {
+	mongoID, err := bson.ObjectIDFromHex(targetID)
+	if err != nil {
+		return BAD_TARGET_ID
+	}
+
+	_, err = repo.Coll.DeleteMany(context.TODO(), bson.M{"target": target, "target_id": mongoID})
+	return err
+}
@@ -0,0 +1,167 @@
+package mongoTLC
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"mainService/internal/domain"
+)
+
+type IImageHashRepository interface {
+	RegisterHash(hash uint64) error
+	GetBlockedHashes() ([]uint64, error)
+	BlockHash(hash uint64, moderatorID, reason string) error
+	IncrementBlockedHits(hash uint64) error
+	GetHashStats() (*domain.ImageHashStats, error)
+}
+
+type mongoImageHashRepository struct {
+	DB          *mongo.Database
+	HashColl    *mongo.Collection
+	BlockedColl *mongo.Collection
+}
+
+func NewMongoImageHashRepository(db *mongo.Database) IImageHashRepository {
+	return &mongoImageHashRepository{
+		DB:          db,
+		HashColl:    db.Collection("image_hash"),
+		BlockedColl: db.Collection("blocked_image"),
+	}
+}
+
+func (repo *mongoImageHashRepository) RegisterHash(hash uint64) error {
+	filter := bson.M{"hash": int64(hash)}
+
+	update := bson.M{
+		"$setOnInsert": bson.M{"first_seen": time.Now()},
+		"$inc":         bson.M{"seen_count": 1},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := repo.HashColl.UpdateOne(context.TODO(), filter, update, opts)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo *mongoImageHashRepository) GetBlockedHashes() ([]uint64, error) {
+	opt := options.Find().SetProjection(bson.M{"hash": 1, "_id": 0})
+	cursor, err := repo.BlockedColl.Find(context.TODO(), bson.M{}, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var results []domain.DBBlockedImage
+	if err = cursor.All(context.TODO(), &results); err != nil {
+		return nil, err
+	}
+
+	hashes := make([]uint64, len(results))
+	for i, res := range results {
+		hashes[i] = uint64(res.Hash)
+	}
+
+	return hashes, nil
+}
+
+func (repo *mongoImageHashRepository) BlockHash(hash uint64, moderatorID, reason string) error {
+	moderatorMongoID, err := bson.ObjectIDFromHex(moderatorID)
+	if err != nil {
+		return BAD_USER_ID
+	}
+
+	filter := bson.M{"hash": int64(hash)}
+
+	update := bson.M{
+		"$set": bson.M{
+			"reason":    reason,
+			"moderator": moderatorMongoID,
+		},
+		"$setOnInsert": bson.M{"hits": 0},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err = repo.BlockedColl.UpdateOne(context.TODO(), filter, update, opts)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo *mongoImageHashRepository) IncrementBlockedHits(hash uint64) error {
+	filter := bson.M{"hash": int64(hash)}
+
+	update := bson.M{
+		"$inc": bson.M{"hits": 1},
+	}
+
+	res, err := repo.BlockedColl.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return NOT_FOUND
+	}
+
+	return nil
+}
+
+func (repo *mongoImageHashRepository) sumField(coll *mongo.Collection, field string) (count int64, sum int64, err error) {
+	pipeline := mongo.Pipeline{
+		{{"$group", bson.M{
+			"_id":   nil,
+			"count": bson.M{"$sum": 1},
+			"sum":   bson.M{"$sum": "$" + field},
+		}}},
+	}
+
+	cursor, err := coll.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var results []struct {
+		Count int64 `bson:"count"`
+		Sum   int64 `bson:"sum"`
+	}
+	if err = cursor.All(context.TODO(), &results); err != nil {
+		return 0, 0, err
+	}
+
+	if len(results) == 0 {
+		return 0, 0, nil
+	}
+
+	return results[0].Count, results[0].Sum, nil
+}
+
+func (repo *mongoImageHashRepository) GetHashStats() (*domain.ImageHashStats, error) {
+	unique, accepted, err := repo.sumField(repo.HashColl, "seen_count")
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, rejections, err := repo.sumField(repo.BlockedColl, "hits")
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.ImageHashStats{
+		AcceptedImages:    accepted,
+		UniqueImages:      unique,
+		ExactDuplicates:   accepted - unique,
+		BlockedHashes:     blocked,
+		BlockedRejections: rejections,
+	}
+
+	return stats, nil
+}
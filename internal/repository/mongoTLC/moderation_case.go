@@ -0,0 +1,259 @@
+package mongoTLC
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"mainService/internal/domain"
+)
+
+type IModerationCaseRepository interface {
+	AddReport(reporterID, ownerID string, report *domain.ApiReport) (*domain.ApiModerationCase, error)
+	GetCases(status domain.CaseStatus) ([]*domain.ApiModerationCase, error)
+	GetCase(caseID string) (*domain.ApiModerationCase, error)
+	AddCaseAction(caseID string, entry *domain.ApiCaseActionEntry, status domain.CaseStatus, hidden bool) error
+	GetTargetOwner(target domain.ReportTarget, targetID string) (string, error)
+	SetTargetHidden(target domain.ReportTarget, targetID string, hidden bool) error
+}
+
+type mongoModerationCaseRepository struct {
+	DB   *mongo.Database
+	Coll *mongo.Collection
+}
+
+func NewMongoModerationCaseRepository(db *mongo.Database) IModerationCaseRepository {
+	return &mongoModerationCaseRepository{
+		DB:   db,
+		Coll: db.Collection("moderation_case"),
+	}
+}
+
+// AddReport adds the report to the open case of the target, opening one if
+// there is none. A second report of the same user on an open case runs into
+// the unique index on open cases and is refused.
+func (repo *mongoModerationCaseRepository) AddReport(reporterID, ownerID string, report *domain.ApiReport) (*domain.ApiModerationCase, error) {
+	reporterMongoID, err := bson.ObjectIDFromHex(reporterID)
+	if err != nil {
+		return nil, BAD_USER_ID
+	}
+
+	ownerMongoID, err := bson.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return nil, BAD_USER_ID
+	}
+
+	targetMongoID, err := bson.ObjectIDFromHex(report.TargetID)
+	if err != nil {
+		return nil, BAD_TARGET_ID
+	}
+
+	now := time.Now()
+	filter := bson.M{
+		"target":           report.Target,
+		"target_id":        targetMongoID,
+		"status":           domain.CaseOpen,
+		"reports.reporter": bson.M{"$ne": reporterMongoID},
+	}
+
+	update := bson.M{
+		"$push": bson.M{"reports": domain.DBCaseReport{
+			ReporterID: reporterMongoID,
+			Reason:     report.Reason,
+			Comment:    report.Comment,
+			CreatedAt:  now,
+		}},
+		"$inc": bson.M{
+			"report_count":                     1,
+			"reasons." + string(report.Reason): 1,
+		},
+		"$set": bson.M{"updated_at": now},
+		"$setOnInsert": bson.M{
+			"owner":      ownerMongoID,
+			"hidden":     false,
+			"created_at": now,
+		},
+	}
+
+	opt := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	dbCase := new(domain.DBModerationCase)
+	err = repo.Coll.FindOneAndUpdate(context.TODO(), filter, update, opt).Decode(dbCase)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ALREADY_REPORTED
+	} else if err != nil {
+		return nil, err
+	}
+
+	return dbCase.ToApi(false), nil
+}
+
+// GetCases returns the cases with the given status, the most reported first.
+func (repo *mongoModerationCaseRepository) GetCases(status domain.CaseStatus) ([]*domain.ApiModerationCase, error) {
+	opt := options.Find().
+		SetSort(bson.D{{"report_count", -1}, {"updated_at", -1}}).
+		SetProjection(bson.M{"reports": 0, "actions": 0})
+
+	cursor, err := repo.Coll.Find(context.TODO(), bson.M{"status": status}, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(context.TODO())
+
+	cases := []*domain.ApiModerationCase{}
+	for cursor.Next(context.TODO()) {
+		dbCase := new(domain.DBModerationCase)
+
+		if err = cursor.Decode(dbCase); err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, dbCase.ToApi(false))
+	}
+
+	if err = cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return cases, nil
+}
+
+func (repo *mongoModerationCaseRepository) GetCase(caseID string) (*domain.ApiModerationCase, error) {
+	mongoID, err := bson.ObjectIDFromHex(caseID)
+	if err != nil {
+		return nil, BAD_CASE_ID
+	}
+
+	dbCase := new(domain.DBModerationCase)
+	err = repo.Coll.FindOne(context.TODO(), bson.M{"_id": mongoID}).Decode(dbCase)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, NOT_FOUND
+	} else if err != nil {
+		return nil, err
+	}
+
+	return dbCase.ToApi(true), nil
+}
+
+// AddCaseAction appends the action to the audit trail of the case and sets
+// the resulting status of the case and visibility of its target.
+func (repo *mongoModerationCaseRepository) AddCaseAction(caseID string, entry *domain.ApiCaseActionEntry, status domain.CaseStatus, hidden bool) error {
+	mongoID, err := bson.ObjectIDFromHex(caseID)
+	if err != nil {
+		return BAD_CASE_ID
+	}
+
+	dbEntry := domain.DBCaseActionEntry{
+		Action:         entry.Action,
+		Comment:        entry.Comment,
+		SuspendedUntil: entry.SuspendedUntil,
+		Automatic:      entry.Automatic,
+		CreatedAt:      entry.CreatedAt,
+	}
+
+	if entry.ModeratorID != "" {
+		dbEntry.ModeratorID, err = bson.ObjectIDFromHex(entry.ModeratorID)
+		if err != nil {
+			return BAD_USER_ID
+		}
+	}
+
+	update := bson.M{
+		"$push": bson.M{"actions": dbEntry},
+		"$set": bson.M{
+			"status":     status,
+			"hidden":     hidden,
+			"updated_at": entry.CreatedAt,
+		},
+	}
+
+	res, err := repo.Coll.UpdateByID(context.TODO(), mongoID, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return NOT_FOUND
+	}
+
+	return nil
+}
+
+// GetTargetOwner returns the user the reported content belongs to, the user
+// themselves for reported profiles.
+func (repo *mongoModerationCaseRepository) GetTargetOwner(target domain.ReportTarget, targetID string) (string, error) {
+	mongoID, err := bson.ObjectIDFromHex(targetID)
+	if err != nil {
+		return "", BAD_TARGET_ID
+	}
+
+	var filter bson.M
+	var coll *mongo.Collection
+	var ownerField string
+
+	switch target {
+	case domain.ReportTargetUser:
+		coll, filter, ownerField = repo.DB.Collection("user"), bson.M{"_id": mongoID}, "_id"
+	case domain.ReportTargetPet:
+		coll, filter, ownerField = repo.DB.Collection("user"), bson.M{"pets.$id": mongoID}, "_id"
+	case domain.ReportTargetService:
+		coll, filter, ownerField = repo.DB.Collection("service"), bson.M{"_id": mongoID}, "owner"
+	default:
+		return "", BAD_TARGET_ID
+	}
+
+	var owner bson.M
+	opt := options.FindOne().SetProjection(bson.M{ownerField: 1})
+	err = coll.FindOne(context.TODO(), filter, opt).Decode(&owner)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", NOT_FOUND
+	} else if err != nil {
+		return "", err
+	}
+
+	switch ownerID := owner[ownerField].(type) {
+	case bson.ObjectID:
+		return ownerID.Hex(), nil
+	case bson.M:
+		if id, ok := ownerID["$id"].(bson.ObjectID); ok {
+			return id.Hex(), nil
+		}
+	case bson.D:
+		for _, elem := range ownerID {
+			if id, ok := elem.Value.(bson.ObjectID); ok && elem.Key == "$id" {
+				return id.Hex(), nil
+			}
+		}
+	}
+
+	return "", NOT_FOUND
+}
+
+// SetTargetHidden hides the content from everyone or brings it back. The
+// collections are named after the report targets.
+func (repo *mongoModerationCaseRepository) SetTargetHidden(target domain.ReportTarget, targetID string, hidden bool) error {
+	mongoID, err := bson.ObjectIDFromHex(targetID)
+	if err != nil {
+		return BAD_TARGET_ID
+	}
+
+	if !domain.IsReportTarget(target) {
+		return BAD_TARGET_ID
+	}
+
+	res, err := repo.DB.Collection(string(target)).UpdateByID(context.TODO(), mongoID, bson.M{"$set": bson.M{"hidden": hidden}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return NOT_FOUND
+	}
+
+	return nil
+}
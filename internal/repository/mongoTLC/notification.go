@@ -0,0 +1,101 @@
+package mongoTLC
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"mainService/internal/domain"
+)
+
+type INotificationRepository interface {
+	AddNotification(userID string, kind domain.NotificationKind, message, subjectID string) error
+	GetUserNotifications(userID string) ([]*domain.ApiNotification, error)
+	MarkNotificationsRead(userID string) error
+}
+
+type mongoNotificationRepository struct {
+	DB   *mongo.Database
+	Coll *mongo.Collection
+}
+
+func NewMongoNotificationRepository(db *mongo.Database) INotificationRepository {
+	return &mongoNotificationRepository{
+		DB:   db,
+		Coll: db.Collection("notification"),
+	}
+}
+
+func (repo *mongoNotificationRepository) AddNotification(userID string, kind domain.NotificationKind, message, subjectID string) error {
+	mongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return BAD_USER_ID
+	}
+
+	notification := domain.DBNotification{
+		UserID:    mongoID,
+		Kind:      kind,
+		Message:   message,
+		SubjectID: subjectID,
+		CreatedAt: time.Now(),
+	}
+
+	_, err = repo.Coll.InsertOne(context.TODO(), notification)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo *mongoNotificationRepository) GetUserNotifications(userID string) ([]*domain.ApiNotification, error) {
+	mongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, BAD_USER_ID
+	}
+
+	opt := options.Find().SetSort(bson.D{{"created_at", -1}})
+	cursor, err := repo.Coll.Find(context.TODO(), bson.M{"user": mongoID}, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var dbResults []*domain.DBNotification
+	if err = cursor.All(context.TODO(), &dbResults); err != nil {
+		return nil, err
+	}
+
+	results := make([]*domain.ApiNotification, len(dbResults))
+	for i, res := range dbResults {
+		results[i] = res.ToApi()
+	}
+
+	return results, nil
+}
+
+func (repo *mongoNotificationRepository) MarkNotificationsRead(userID string) error {
+	mongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return BAD_USER_ID
+	}
+
+	filter := bson.M{
+		"user": mongoID,
+		"read": false,
+	}
+
+	update := bson.M{
+		"$set": bson.M{"read": true},
+	}
+
+	_, err = repo.Coll.UpdateMany(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,310 @@
+package mongoTLC
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"mainService/internal/domain"
+)
+
+type IPendingImageRepository interface {
+	AddPendingImage(ownerID string, upload *domain.ImageUpload, status domain.ModerationStatus, nsfwProbability float64) (string, error)
+	GetPendingImage(imageID string) (*domain.DBPendingImage, error)
+	GetImagesByStatus(statuses ...domain.ModerationStatus) ([]*domain.DBPendingImage, error)
+	GetUserPendingImages(userID string) ([]*domain.DBPendingImage, error)
+	SetImageVerdict(imageID string, status domain.ModerationStatus, nsfwProbability float64) error
+	ReviewImage(imageID, reviewerID string, status domain.ModerationStatus) error
+	ApplyImage(image *domain.DBPendingImage) error
+	RetractImage(image *domain.DBPendingImage) error
+}
+
+type mongoPendingImageRepository struct {
+	DB   *mongo.Database
+	Coll *mongo.Collection
+}
+
+func NewMongoPendingImageRepository(db *mongo.Database) IPendingImageRepository {
+	return &mongoPendingImageRepository{
+		DB:   db,
+		Coll: db.Collection("pending_image"),
+	}
+}
+
+func (repo *mongoPendingImageRepository) AddPendingImage(ownerID string, upload *domain.ImageUpload, status domain.ModerationStatus, nsfwProbability float64) (string, error) {
+	ownerMongoID, err := bson.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return "", BAD_USER_ID
+	}
+
+	targetMongoID, err := bson.ObjectIDFromHex(upload.TargetID)
+	if err != nil {
+		return "", BAD_TARGET_ID
+	}
+
+	imageBytes, err := base64.StdEncoding.DecodeString(upload.Image)
+	if err != nil {
+		return "", err
+	}
+
+	pending := domain.DBPendingImage{
+		OwnerID:         ownerMongoID,
+		Target:          upload.Target,
+		TargetID:        targetMongoID,
+		Image:           imageBytes,
+		Status:          status,
+		NSFWProbability: nsfwProbability,
+		CreatedAt:       time.Now(),
+	}
+
+	if upload.ImageID != "" {
+		pending.ImageID, err = bson.ObjectIDFromHex(upload.ImageID)
+		if err != nil {
+			return "", BAD_IMAGE_ID
+		}
+	}
+
+	res, err := repo.Coll.InsertOne(context.TODO(), pending)
+	if err != nil {
+		return "", err
+	}
+
+	imageID, _ := res.InsertedID.(bson.ObjectID)
+
+	return imageID.Hex(), nil
+}
+
+func (repo *mongoPendingImageRepository) GetPendingImage(imageID string) (*domain.DBPendingImage, error) {
+	mongoID, err := bson.ObjectIDFromHex(imageID)
+	if err != nil {
+		return nil, BAD_IMAGE_ID
+	}
+
+	image := new(domain.DBPendingImage)
+	err = repo.Coll.FindOne(context.TODO(), bson.M{"_id": mongoID}).Decode(image)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, NOT_FOUND
+	} else if err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+func (repo *mongoPendingImageRepository) findImages(filter bson.M) ([]*domain.DBPendingImage, error) {
+	opt := options.Find().SetSort(bson.D{{"created_at", 1}})
+	cursor, err := repo.Coll.Find(context.TODO(), filter, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var results []*domain.DBPendingImage
+	if err = cursor.All(context.TODO(), &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (repo *mongoPendingImageRepository) GetImagesByStatus(statuses ...domain.ModerationStatus) ([]*domain.DBPendingImage, error) {
+	return repo.findImages(bson.M{"status": bson.M{"$in": statuses}})
+}
+
+func (repo *mongoPendingImageRepository) GetUserPendingImages(userID string) ([]*domain.DBPendingImage, error) {
+	mongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, BAD_USER_ID
+	}
+
+	filter := bson.M{
+		"owner":  mongoID,
+		"status": bson.M{"$in": []domain.ModerationStatus{domain.StatusPending, domain.StatusReview, domain.StatusFlagged}},
+	}
+
+	return repo.findImages(filter)
+}
+
+func (repo *mongoPendingImageRepository) SetImageVerdict(imageID string, status domain.ModerationStatus, nsfwProbability float64) error {
+	mongoID, err := bson.ObjectIDFromHex(imageID)
+	if err != nil {
+		return BAD_IMAGE_ID
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":           status,
+			"nsfw_probability": nsfwProbability,
+		},
+	}
+
+	res, err := repo.Coll.UpdateByID(context.TODO(), mongoID, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return NOT_FOUND
+	}
+
+	return nil
+}
+
+func (repo *mongoPendingImageRepository) ReviewImage(imageID, reviewerID string, status domain.ModerationStatus) error {
+	mongoID, err := bson.ObjectIDFromHex(imageID)
+	if err != nil {
+		return BAD_IMAGE_ID
+	}
+
+	reviewerMongoID, err := bson.ObjectIDFromHex(reviewerID)
+	if err != nil {
+		return BAD_USER_ID
+	}
+
+	filter := bson.M{
+		"_id":    mongoID,
+		"status": bson.M{"$in": []domain.ModerationStatus{domain.StatusReview, domain.StatusFlagged}},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":      status,
+			"reviewed_by": reviewerMongoID,
+			"reviewed_at": time.Now(),
+		},
+	}
+
+	res, err := repo.Coll.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return NOT_UNDER_REVIEW
+	}
+
+	return nil
+}
+
+// ApplyImage publishes an approved image on the entity it was uploaded for.
+// Service photos reuse the pending image ID as their photo ID.
+func (repo *mongoPendingImageRepository) ApplyImage(image *domain.DBPendingImage) error {
+	var coll *mongo.Collection
+	filter := bson.M{"_id": image.TargetID}
+	var update bson.M
+
+	switch image.Target {
+	case domain.TargetAvatar:
+		coll = repo.DB.Collection("user")
+		update = bson.M{"$set": bson.M{"avatar_url": image.Image}}
+	case domain.TargetBackground:
+		coll = repo.DB.Collection("user")
+		update = bson.M{"$set": bson.M{"background_url": image.Image}}
+	case domain.TargetPetAvatar:
+		coll = repo.DB.Collection("pet")
+		update = bson.M{"$set": bson.M{"avatar_url": image.Image}}
+	case domain.TargetServicePhoto:
+		coll = repo.DB.Collection("service")
+		filter["photos."+strconv.Itoa(MAX_SERVICE_PHOTOS-1)] = bson.M{"$exists": false}
+		photo := domain.DBServicePhoto{
+			PhotoID: image.ImageID,
+			Image:   image.Image,
+		}
+		update = bson.M{"$push": bson.M{"photos": photo}}
+	default:
+		return UNKNOWN_IMAGE_TARGET
+	}
+
+	res, err := coll.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 && image.Target == domain.TargetServicePhoto {
+		return TOO_MANY_PHOTOS
+	} else if res.MatchedCount == 0 {
+		return NOT_FOUND
+	}
+
+	if image.Target == domain.TargetServicePhoto {
+		coverFilter := bson.M{
+			"_id":         image.TargetID,
+			"cover_photo": bson.M{"$exists": false},
+		}
+
+		setCover := bson.M{
+			"$set": bson.M{"cover_photo": image.ImageID},
+		}
+
+		_, err = coll.UpdateOne(context.TODO(), coverFilter, setCover)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RetractImage takes down a published image, leaving the target untouched if
+// the owner has already replaced it with another one.
+func (repo *mongoPendingImageRepository) RetractImage(image *domain.DBPendingImage) error {
+	var coll *mongo.Collection
+	filter := bson.M{"_id": image.TargetID}
+	var update bson.M
+
+	switch image.Target {
+	case domain.TargetAvatar:
+		coll = repo.DB.Collection("user")
+		filter["avatar_url"] = image.Image
+		update = bson.M{"$unset": bson.M{"avatar_url": ""}}
+	case domain.TargetBackground:
+		coll = repo.DB.Collection("user")
+		filter["background_url"] = image.Image
+		update = bson.M{"$unset": bson.M{"background_url": ""}}
+	case domain.TargetPetAvatar:
+		coll = repo.DB.Collection("pet")
+		filter["avatar_url"] = image.Image
+		update = bson.M{"$unset": bson.M{"avatar_url": ""}}
+	case domain.TargetServicePhoto:
+		coll = repo.DB.Collection("service")
+		update = bson.M{"$pull": bson.M{"photos": bson.M{"_id": image.ImageID}}}
+	default:
+		return UNKNOWN_IMAGE_TARGET
+	}
+
+	_, err := coll.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+
+	if image.Target == domain.TargetServicePhoto {
+		var service struct {
+			Photos []struct {
+				PhotoID bson.ObjectID `bson:"_id"`
+			} `bson:"photos"`
+		}
+
+		opt := options.FindOne().SetProjection(bson.M{"photos._id": 1, "_id": 0})
+		err = coll.FindOne(context.TODO(), bson.M{"_id": image.TargetID, "cover_photo": image.ImageID}, opt).Decode(&service)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		coverUpdate := bson.M{"$unset": bson.M{"cover_photo": ""}}
+		if len(service.Photos) != 0 {
+			coverUpdate = bson.M{"$set": bson.M{"cover_photo": service.Photos[0].PhotoID}}
+		}
+
+		_, err = coll.UpdateByID(context.TODO(), image.TargetID, coverUpdate)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -3,7 +3,6 @@ package mongoTLC
 import (
 	"context"
 	"errors"
-	"strings"
 
 	//"fmt"
 	"mainService/internal/domain"
@@ -15,6 +14,7 @@ import (
 
 type IPetRepository interface {
 	GetPetInfo(petID string) (*domain.ApiPetInfo, error)
+	GetPetInfoIncludingHidden(petID string) (*domain.ApiPetInfo, error)
 	GetAvatarBytes(petID string) ([]byte, error)
 	IncrementAnimal(typeOfAnimal string, serviceID string) error
 	DecrementAnimal(typeOfAnimal string, serviceID string) error
@@ -36,13 +36,24 @@ func NewMongoPetRepository(db *mongo.Database) IPetRepository {
 }
 
 func (repo *mongoPetRepository) GetPetInfo(petID string) (*domain.ApiPetInfo, error) {
+	return repo.getPetInfo(petID, bson.M{"hidden": bson.M{"$ne": true}})
+}
+
+// GetPetInfoIncludingHidden is for the owner and the internal paths, which
+// have to reach pets hidden by moderators as well.
+func (repo *mongoPetRepository) GetPetInfoIncludingHidden(petID string) (*domain.ApiPetInfo, error) {
+	return repo.getPetInfo(petID, bson.M{})
+}
+
+func (repo *mongoPetRepository) getPetInfo(petID string, filter bson.M) (*domain.ApiPetInfo, error) {
 	mongoID, err := bson.ObjectIDFromHex(petID)
 	if err != nil {
 		return nil, BAD_PET_ID
 	}
+	filter["_id"] = mongoID
 
 	dbInfo := new(domain.DBPetInfo)
-	err = repo.PetColl.FindOne(context.TODO(), bson.M{"_id": mongoID}).Decode(dbInfo)
+	err = repo.PetColl.FindOne(context.TODO(), filter).Decode(dbInfo)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, NOT_FOUND
 	} else if err != nil {
@@ -79,7 +90,7 @@ func (repo *mongoPetRepository) IncrementAnimal(typeOfAnimal string, serviceID s
 		return err
 	}
 
-	normalizedType := strings.TrimSpace(strings.ToLower(typeOfAnimal))
+	normalizedType := NormalizeAnimal(typeOfAnimal)
 
 	filter := bson.M{"type_of_animal": normalizedType}
 
@@ -118,7 +129,7 @@ func (repo *mongoPetRepository) DecrementAnimal(typeOfAnimal string, serviceID s
 		return err
 	}
 
-	normalizedType := strings.TrimSpace(strings.ToLower(typeOfAnimal))
+	normalizedType := NormalizeAnimal(typeOfAnimal)
 
 	filter := bson.M{"type_of_animal": normalizedType}
 
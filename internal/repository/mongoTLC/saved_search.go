@@ -0,0 +1,256 @@
+package mongoTLC
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"mainService/internal/domain"
+)
+
+type ISavedSearchRepository interface {
+	AddSavedSearch(userID string, search *domain.ApiSavedSearch, emailToken string) (*domain.ApiSavedSearch, error)
+	ConfirmSavedSearchEmail(searchID, emailToken string) error
+	CountUserSavedSearches(userID string) (int64, error)
+	GetUserSavedSearches(userID string) ([]*domain.ApiSavedSearch, error)
+	DeleteSavedSearch(userID, searchID string) error
+	GetSavedSearchesForService(serviceID string) ([]*domain.ApiSavedSearch, error)
+	AddSearchAlert(search *domain.ApiSavedSearch, service *domain.ApiService) error
+	GetUnsentSearchAlerts() ([]*domain.DBSearchAlert, error)
+	DeleteSearchAlerts(alertIDs []bson.ObjectID) error
+}
+
+type mongoSavedSearchRepository struct {
+	DB          *mongo.Database
+	Coll        *mongo.Collection
+	AlertColl   *mongo.Collection
+	ServiceColl *mongo.Collection
+}
+
+func NewMongoSavedSearchRepository(db *mongo.Database) ISavedSearchRepository {
+	return &mongoSavedSearchRepository{
+		DB:          db,
+		Coll:        db.Collection("saved_search"),
+		AlertColl:   db.Collection("search_alert"),
+		ServiceColl: db.Collection("service"),
+	}
+}
+
+// AddSavedSearch keeps the e-mail of the search unconfirmed until
+// ConfirmSavedSearchEmail is called with emailToken.
+func (repo *mongoSavedSearchRepository) AddSavedSearch(userID string, search *domain.ApiSavedSearch, emailToken string) (*domain.ApiSavedSearch, error) {
+	mongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, BAD_USER_ID
+	}
+
+	dbSearch := domain.DBSavedSearch{
+		UserID:     mongoID,
+		Query:      search.Query,
+		Animals:    []string{},
+		Email:      search.Email,
+		EmailToken: emailToken,
+		CreatedAt:  time.Now(),
+	}
+
+	if search.Filter != nil {
+		dbSearch.MinPrice = search.Filter.MinPrice
+		dbSearch.MaxPrice = search.Filter.MaxPrice
+
+		for _, animal := range search.Filter.Animals {
+			if animal = NormalizeAnimal(animal); animal != "" {
+				dbSearch.Animals = append(dbSearch.Animals, animal)
+			}
+		}
+	}
+
+	res, err := repo.Coll.InsertOne(context.TODO(), dbSearch)
+	if err != nil {
+		return nil, err
+	}
+	dbSearch.SavedSearchID = res.InsertedID.(bson.ObjectID)
+
+	return dbSearch.ToApi(), nil
+}
+
+func (repo *mongoSavedSearchRepository) ConfirmSavedSearchEmail(searchID, emailToken string) error {
+	mongoID, err := bson.ObjectIDFromHex(searchID)
+	if err != nil {
+		return BAD_SAVED_SEARCH_ID
+	}
+
+	filter := bson.M{"_id": mongoID, "email_token": emailToken, "email_confirmed": false}
+	update := bson.M{
+		"$set":   bson.M{"email_confirmed": true},
+		"$unset": bson.M{"email_token": ""},
+	}
+
+	res, err := repo.Coll.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return NOT_FOUND
+	}
+
+	return nil
+}
+
+func (repo *mongoSavedSearchRepository) CountUserSavedSearches(userID string) (int64, error) {
+	mongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, BAD_USER_ID
+	}
+
+	return repo.Coll.CountDocuments(context.TODO(), bson.M{"user": mongoID})
+}
+
+func (repo *mongoSavedSearchRepository) GetUserSavedSearches(userID string) ([]*domain.ApiSavedSearch, error) {
+	mongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, BAD_USER_ID
+	}
+
+	opt := options.Find().SetSort(bson.D{{"created_at", -1}})
+	return repo.findSavedSearches(bson.M{"user": mongoID}, opt)
+}
+
+// DeleteSavedSearch also drops the matches of the search waiting for the
+// digest.
+func (repo *mongoSavedSearchRepository) DeleteSavedSearch(userID, searchID string) error {
+	userMongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return BAD_USER_ID
+	}
+
+	searchMongoID, err := bson.ObjectIDFromHex(searchID)
+	if err != nil {
+		return BAD_SAVED_SEARCH_ID
+	}
+
+	res, err := repo.Coll.DeleteOne(context.TODO(), bson.M{"_id": searchMongoID, "user": userMongoID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return NOT_FOUND
+	}
+
+	_, err = repo.AlertColl.DeleteMany(context.TODO(), bson.M{"saved_search": searchMongoID})
+	return err
+}
+
+// GetSavedSearchesForService returns the saved searches of other users whose
+// prices and animals the service fits. Whether the query matches the service
+// is left to the caller.
+func (repo *mongoSavedSearchRepository) GetSavedSearchesForService(serviceID string) ([]*domain.ApiSavedSearch, error) {
+	mongoID, err := bson.ObjectIDFromHex(serviceID)
+	if err != nil {
+		return nil, BAD_SERVICE_ID
+	}
+
+	opt := options.FindOne().SetProjection(bson.M{"owner": 1, "price": 1, "animals": 1})
+
+	service := new(domain.DBService)
+	err = repo.ServiceColl.FindOne(context.TODO(), bson.M{"_id": mongoID, "hidden": bson.M{"$ne": true}}, opt).Decode(service)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, NOT_FOUND
+	} else if err != nil {
+		return nil, err
+	}
+
+	animals := service.Animals
+	if animals == nil {
+		animals = []string{}
+	}
+
+	filter := bson.M{
+		"user":      bson.M{"$ne": service.UserID["$id"]},
+		"min_price": bson.M{"$lte": service.Price},
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"max_price": 0},
+				bson.M{"max_price": bson.M{"$gte": service.Price}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"animals": bson.M{"$size": 0}},
+				bson.M{"animals": bson.M{"$in": animals}},
+			}},
+		},
+	}
+
+	return repo.findSavedSearches(filter)
+}
+
+func (repo *mongoSavedSearchRepository) findSavedSearches(filter bson.M, opts ...options.Lister[options.FindOptions]) ([]*domain.ApiSavedSearch, error) {
+	cursor, err := repo.Coll.Find(context.TODO(), filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var dbResults []*domain.DBSavedSearch
+	if err = cursor.All(context.TODO(), &dbResults); err != nil {
+		return nil, err
+	}
+
+	results := make([]*domain.ApiSavedSearch, len(dbResults))
+	for i, res := range dbResults {
+		results[i] = res.ToApi()
+	}
+
+	return results, nil
+}
+
+func (repo *mongoSavedSearchRepository) AddSearchAlert(search *domain.ApiSavedSearch, service *domain.ApiService) error {
+	searchMongoID, err := bson.ObjectIDFromHex(search.SavedSearchID)
+	if err != nil {
+		return BAD_SAVED_SEARCH_ID
+	}
+
+	serviceMongoID, err := bson.ObjectIDFromHex(service.ServiceID)
+	if err != nil {
+		return BAD_SERVICE_ID
+	}
+
+	alert := domain.DBSearchAlert{
+		SavedSearchID: searchMongoID,
+		Email:         search.Email,
+		Query:         search.Query,
+		ServiceID:     serviceMongoID,
+		Title:         service.Title,
+		Price:         service.Price,
+		CreatedAt:     time.Now(),
+	}
+
+	_, err = repo.AlertColl.InsertOne(context.TODO(), alert)
+	return err
+}
+
+// GetUnsentSearchAlerts returns the alerts waiting for the digest grouped by
+// address, the oldest first.
+func (repo *mongoSavedSearchRepository) GetUnsentSearchAlerts() ([]*domain.DBSearchAlert, error) {
+	opt := options.Find().SetSort(bson.D{{"email", 1}, {"created_at", 1}})
+
+	cursor, err := repo.AlertColl.Find(context.TODO(), bson.M{}, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	alerts := []*domain.DBSearchAlert{}
+	if err = cursor.All(context.TODO(), &alerts); err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+func (repo *mongoSavedSearchRepository) DeleteSearchAlerts(alertIDs []bson.ObjectID) error {
+	_, err := repo.AlertColl.DeleteMany(context.TODO(), bson.M{"_id": bson.M{"$in": alertIDs}})
+	return err
+}
@@ -3,22 +3,39 @@ package mongoTLC
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 
 	"mainService/internal/domain"
+	"mainService/pkg/fuzzyText"
+	"mainService/pkg/textLanguage"
+)
+
+const MAX_SERVICE_PHOTOS = 10
+
+const (
+	DEFAULT_SEARCH_PAGE_SIZE = 20
+	MAX_SEARCH_PAGE_SIZE     = 100
 )
 
 type IServiceRepository interface {
 	AddService(userID string, service *domain.ApiService) (string, error)
 	GetServiceByID(serviceID string) (*domain.ApiService, error)
+	GetServiceByIDIncludingHidden(serviceID string) (*domain.ApiService, error)
 	GetServicesByIDs(serviceIDs ...string) ([]*domain.ApiService, error)
 	GetAllServices() ([]*domain.ApiService, error)
 	DeleteService(userID, serviceID string) error
-	SearchServices(queryString string, filters *domain.ServiceFilter) ([]*domain.ApiService, error)
-	GetServiceIDsWithAnimals(animalList []string) ([]string, error)
+	SearchServices(queryString string, filters *domain.ServiceFilter) (*domain.ServiceSearchResult, error)
+	AddServicePhoto(userID, serviceID string, photo *domain.ApiServicePhoto) (string, error)
+	DeleteServicePhoto(userID, serviceID, photoID string) error
+	SetServiceCover(userID, serviceID, photoID string) error
+	ReorderServicePhotos(userID, serviceID string, photoIDs []string) error
+	Autocomplete(input string, limit int64) (*domain.Suggestions, error)
+	GetFeed(viewerID string, animals []string, page, pageSize int64) ([]*domain.FeedItem, error)
+	GetSimilarServices(serviceID string, limit int64) ([]*domain.SimilarService, error)
 }
 
 type mongoServiceRepository struct {
@@ -43,6 +60,15 @@ func (repo *mongoServiceRepository) AddService(userID string, service *domain.Ap
 		return "", err
 	}
 
+	petAnimals, err := getPetAnimals(repo.DB, dbService.PetIDs)
+	if err != nil {
+		return "", err
+	}
+	dbService.Animals = collectAnimals(dbService.PetIDs, petAnimals)
+	dbService.TitleWords = fuzzyText.Words(dbService.Title)
+	dbService.TitleTrigrams = fuzzyText.Trigrams(dbService.Title)
+	dbService.Language = string(textLanguage.Detect(dbService.Title, dbService.Description))
+
 	res, err := repo.ServiceColl.InsertOne(context.TODO(), *dbService)
 	if err != nil {
 		return "", err
@@ -73,13 +99,24 @@ func (repo *mongoServiceRepository) AddService(userID string, service *domain.Ap
 }
 
 func (repo *mongoServiceRepository) GetServiceByID(serviceID string) (*domain.ApiService, error) {
+	return repo.getService(serviceID, bson.M{"hidden": bson.M{"$ne": true}})
+}
+
+// GetServiceByIDIncludingHidden is for the owner and the internal paths,
+// which have to reach services hidden by moderators as well.
+func (repo *mongoServiceRepository) GetServiceByIDIncludingHidden(serviceID string) (*domain.ApiService, error) {
+	return repo.getService(serviceID, bson.M{})
+}
+
+func (repo *mongoServiceRepository) getService(serviceID string, filter bson.M) (*domain.ApiService, error) {
 	mongoID, err := bson.ObjectIDFromHex(serviceID)
 	if err != nil {
 		return nil, BAD_SERVICE_ID
 	}
+	filter["_id"] = mongoID
 
 	dbInfo := new(domain.DBService)
-	err = repo.ServiceColl.FindOne(context.TODO(), bson.M{"_id": mongoID}).Decode(dbInfo)
+	err = repo.ServiceColl.FindOne(context.TODO(), filter).Decode(dbInfo)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, NOT_FOUND
 	} else if err != nil {
@@ -106,44 +143,11 @@ func (repo *mongoServiceRepository) GetServicesByIDs(serviceIDs ...string) ([]*d
 	}
 
 	filter := bson.M{
-		"_id": bson.M{"$in": mongoIDs},
-	}
-
-	cursor, err := repo.ServiceColl.Find(context.TODO(), filter)
-	if errors.Is(err, mongo.ErrNoDocuments) {
-		return nil, NOT_FOUND
-	} else if err != nil {
-		return nil, err
-	}
-
-	defer cursor.Close(context.TODO())
-
-	var DBresults []*domain.DBService
-	for cursor.Next(context.TODO()) {
-		service := new(domain.DBService)
-
-		if err = cursor.Decode(service); err != nil {
-			return nil, err
-		}
-
-		DBresults = append(DBresults, service)
-	}
-
-	if err = cursor.Err(); err != nil {
-		return nil, err
-	}
-
-	var ApiResults []*domain.ApiService
-	for _, DBserv := range DBresults {
-		ApiServ, err := DBserv.ToApi()
-		if err != nil {
-			return nil, err
-		}
-
-		ApiResults = append(ApiResults, ApiServ)
+		"_id":    bson.M{"$in": mongoIDs},
+		"hidden": bson.M{"$ne": true},
 	}
 
-	return ApiResults, nil
+	return repo.getListedServices(filter)
 }
 
 func (repo *mongoServiceRepository) isUserServiceOwner(userID, serviceID string) (bool, error) {
@@ -171,27 +175,26 @@ func (repo *mongoServiceRepository) isUserServiceOwner(userID, serviceID string)
 }
 
 func (repo *mongoServiceRepository) GetAllServices() ([]*domain.ApiService, error) {
-	cursor, err := repo.ServiceColl.Find(context.TODO(), bson.M{})
-	if errors.Is(err, mongo.ErrNoDocuments) {
-		return nil, NOT_FOUND
-	} else if err != nil {
-		return nil, err
+	return repo.getListedServices(bson.M{"hidden": bson.M{"$ne": true}})
+}
+
+// getListedServices loads the services for listings, with the cover photo
+// only and without the owner's image.
+func (repo *mongoServiceRepository) getListedServices(filter bson.M) ([]*domain.ApiService, error) {
+	pipeline := mongo.Pipeline{
+		{{"$match", filter}},
+		{{"$project", bson.M{"user_image": 0}}},
+		{{"$addFields", bson.M{"photos": coverPhotoOnly}}},
 	}
 
+	cursor, err := repo.ServiceColl.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
 	defer cursor.Close(context.TODO())
 
 	var DBresults []*domain.DBService
-	for cursor.Next(context.TODO()) {
-		service := new(domain.DBService)
-
-		if err = cursor.Decode(service); err != nil {
-			return nil, err
-		}
-
-		DBresults = append(DBresults, service)
-	}
-
-	if err = cursor.Err(); err != nil {
+	if err = cursor.All(context.TODO(), &DBresults); err != nil {
 		return nil, err
 	}
 
@@ -256,120 +259,407 @@ func (repo *mongoServiceRepository) DeleteService(userID, serviceID string) erro
 	return nil
 }
 
-func (repo *mongoServiceRepository) GetServiceIDsWithAnimals(animalList []string) ([]string, error) {
-	filter := bson.M{
-		"type_of_animal": bson.M{
-			"$in": animalList,
-		},
+// SearchServices filters, sorts and pages the services in a single
+// aggregation, computing the facets of all the matching services alongside
+// the page when they are asked for.
+func (repo *mongoServiceRepository) SearchServices(queryString string, filters *domain.ServiceFilter) (*domain.ServiceSearchResult, error) {
+	filter := bson.M{"hidden": bson.M{"$ne": true}}
+
+	if filters.MaxPrice == 0 && filters.MinPrice > 0 {
+		filter["price"] = bson.M{"$gte": filters.MinPrice}
+	} else if filters.MinPrice == 0 && filters.MaxPrice > 0 {
+		filter["price"] = bson.M{"$lte": filters.MaxPrice}
+	} else if filters.MinPrice > 0 && filters.MaxPrice > filters.MinPrice {
+		filter["price"] = bson.M{"$gte": filters.MinPrice, "$lte": filters.MaxPrice}
+	} else if filters.MinPrice > 0 && filters.MaxPrice == filters.MinPrice {
+		filter["price"] = bson.M{"$eq": filters.MinPrice}
+	}
+
+	if len(filters.Animals) != 0 {
+		animals := make([]string, len(filters.Animals))
+		for i, animal := range filters.Animals {
+			animals[i] = NormalizeAnimal(animal)
+		}
+
+		filter["animals"] = bson.M{"$in": animals}
+	}
+
+	pipeline := mongo.Pipeline{{{"$match", filter}}}
+	if queryString != "" {
+		textStages, err := repo.textSearchStages(queryString, filters.Language, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline = textStages
 	}
 
-	opt := options.Find().SetProjection(bson.M{"services.$id": 1, "_id": 0})
-	cursor, err := repo.AnimalColl.Find(context.TODO(), filter, opt)
+	pageSize := filters.PageSize
+	if pageSize <= 0 {
+		pageSize = DEFAULT_SEARCH_PAGE_SIZE
+	}
+	pageSize = min(pageSize, MAX_SEARCH_PAGE_SIZE)
+
+	pageStages := bson.A{
+		bson.D{{"$sort", searchSortRule(filters.Sort, queryString != "")}},
+		bson.D{{"$skip", max(filters.Page, 0) * pageSize}},
+		bson.D{{"$limit", pageSize}},
+		// search results show the cover photo only
+		bson.D{{"$project", bson.M{"user_image": 0}}},
+		bson.D{{"$addFields", bson.M{"photos": coverPhotoOnly}}},
+	}
+
+	if !filters.Facets {
+		for _, stage := range pageStages {
+			pipeline = append(pipeline, stage.(bson.D))
+		}
+
+		cursor, err := repo.ServiceColl.Aggregate(context.TODO(), pipeline)
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(context.TODO())
+
+		var dbResults []domain.DBServiceSerachResult
+		if err = cursor.All(context.TODO(), &dbResults); err != nil {
+			return nil, err
+		}
+
+		services, err := searchResultsToApi(dbResults)
+		if err != nil {
+			return nil, err
+		}
+
+		return &domain.ServiceSearchResult{Services: services}, nil
+	}
+
+	pipeline = append(pipeline, bson.D{{"$facet", facetStages(pageStages)}})
+
+	cursor, err := repo.ServiceColl.Aggregate(context.TODO(), pipeline)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(context.TODO())
 
-	var results []bson.M
-	if err = cursor.All(context.TODO(), &results); err != nil {
+	var faceted dbFacetedSearch
+	if cursor.Next(context.TODO()) {
+		if err = cursor.Decode(&faceted); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	services, err := searchResultsToApi(faceted.Results)
+	if err != nil {
 		return nil, err
 	}
 
-	serviceIDs := []string{}
-	for _, res := range results {
-		IDs := res["services"].(bson.A)
-		for _, rawId := range IDs {
-			castedID := rawId.(bson.D)
-			for _, elem := range castedID {
-				if elem.Key == "$id" {
-					objID := elem.Value.(bson.ObjectID)
-					serviceIDs = append(serviceIDs, objID.Hex())
-				}
-			}
+	return &domain.ServiceSearchResult{Services: services, Facets: faceted.toApi()}, nil
+}
+
+// coverPhotoOnly leaves the cover in the photos of a service, or the first
+// photo when the cover is not set.
+var coverPhotoOnly = bson.M{"$let": bson.M{
+	"vars": bson.M{
+		"cover": bson.M{"$filter": bson.M{
+			"input": bson.M{"$ifNull": bson.A{"$photos", bson.A{}}},
+			"cond":  bson.M{"$eq": bson.A{"$$this._id", "$cover_photo"}},
+		}},
+	},
+	"in": bson.M{"$cond": bson.A{
+		bson.M{"$gt": bson.A{bson.M{"$size": "$$cover"}, 0}},
+		"$$cover",
+		bson.M{"$slice": bson.A{bson.M{"$ifNull": bson.A{"$photos", bson.A{}}}, 1}},
+	}},
+}}
+
+func searchResultsToApi(dbResults []domain.DBServiceSerachResult) ([]*domain.ApiService, error) {
+	results := make([]*domain.ApiService, len(dbResults))
+	for i := range dbResults {
+		apiServ, err := dbResults[i].ToApiService()
+		if err != nil {
+			return nil, err
 		}
+
+		results[i] = apiServ
 	}
 
-	return serviceIDs, nil
+	return results, nil
 }
 
-func (repo *mongoServiceRepository) SearchServices(queryString string, filters *domain.ServiceFilter) ([]*domain.ApiService, error) {
-	filter := bson.M{}
+// searchSortRule ends every rule with the ID so that pages never overlap.
+func searchSortRule(sort domain.SearchSort, hasText bool) bson.D {
+	switch {
+	case sort == domain.SortPriceAsc:
+		return bson.D{{"price", 1}, {"_id", -1}}
+	case sort == domain.SortPriceDesc:
+		return bson.D{{"price", -1}, {"_id", -1}}
+	case sort != domain.SortNewest && hasText:
+		return bson.D{{"score", -1}, {"_id", -1}}
+	default:
+		return bson.D{{"_id", -1}}
+	}
+}
 
-	if filters.MaxPrice == 0 && filters.MinPrice > 0 {
-		filter["price"] = bson.M{"$gte": filters.MinPrice}
-	} else if filters.MinPrice == 0 && filters.MaxPrice > 0 {
-		filter["price"] = bson.M{"$lte": filters.MaxPrice}
-	} else if filters.MinPrice > 0 && filters.MaxPrice > filters.MinPrice {
-		filter["price"] = bson.M{"$gte": filters.MinPrice, "$lte": filters.MaxPrice}
-	} else if filters.MinPrice > 0 && filters.MaxPrice == filters.MinPrice {
-		filter["price"] = bson.M{"$eq": filters.MinPrice}
+func (repo *mongoServiceRepository) AddServicePhoto(userID, serviceID string, photo *domain.ApiServicePhoto) (string, error) {
+	isOwner, err := repo.isUserServiceOwner(userID, serviceID)
+	if err != nil {
+		return "", err
 	}
 
-	projection := bson.D{}
-	sortRule := bson.D{}
-	if queryString != "" {
-		filter["$text"] = bson.M{"$search": queryString}
+	if !isOwner {
+		return "", ACCESS_DENIED
+	}
 
-		projection = bson.D{
-			{"score", bson.M{"$meta": "textScore"}},
-		}
-		sortRule = bson.D{{"score", bson.M{"$meta": "textScore"}}}
+	serviceMongoID, err := bson.ObjectIDFromHex(serviceID)
+	if err != nil {
+		return "", BAD_SERVICE_ID
+	}
+
+	dbPhoto, err := photo.ToDB()
+	if err != nil {
+		return "", err
+	}
+	dbPhoto.PhotoID = bson.NewObjectID()
+
+	filter := bson.M{
+		"_id": serviceMongoID,
+		"photos." + strconv.Itoa(MAX_SERVICE_PHOTOS-1): bson.M{"$exists": false},
 	}
 
-	opt := options.Find().SetProjection(projection).SetSort(sortRule)
-	cursor, err := repo.ServiceColl.Find(context.TODO(), filter, opt)
+	update := bson.M{
+		"$push": bson.M{"photos": dbPhoto},
+	}
+
+	res, err := repo.ServiceColl.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return "", err
+	}
+	if res.MatchedCount == 0 {
+		return "", TOO_MANY_PHOTOS
+	}
+
+	coverFilter := bson.M{
+		"_id":         serviceMongoID,
+		"cover_photo": bson.M{"$exists": false},
+	}
+
+	setCover := bson.M{
+		"$set": bson.M{"cover_photo": dbPhoto.PhotoID},
+	}
+
+	_, err = repo.ServiceColl.UpdateOne(context.TODO(), coverFilter, setCover)
+	if err != nil {
+		return "", err
+	}
+
+	return dbPhoto.PhotoID.Hex(), nil
+}
+
+func (repo *mongoServiceRepository) getServicePhotoIDs(serviceMongoID bson.ObjectID) ([]bson.ObjectID, bson.ObjectID, error) {
+	var servicePhotos struct {
+		Photos []struct {
+			PhotoID bson.ObjectID `bson:"_id"`
+		} `bson:"photos"`
+		CoverPhotoID bson.ObjectID `bson:"cover_photo"`
+	}
+
+	opt := options.FindOne().SetProjection(bson.M{"photos._id": 1, "cover_photo": 1, "_id": 0})
+	err := repo.ServiceColl.FindOne(context.TODO(), bson.M{"_id": serviceMongoID}, opt).Decode(&servicePhotos)
 	if errors.Is(err, mongo.ErrNoDocuments) {
-		return nil, NOT_FOUND
+		return nil, bson.ObjectID{}, NOT_FOUND
 	} else if err != nil {
-		return nil, err
+		return nil, bson.ObjectID{}, err
 	}
-	defer cursor.Close(context.TODO())
 
-	var rawResults []*domain.DBServiceSerachResult
-	for cursor.Next(context.TODO()) {
-		var curRes domain.DBServiceSerachResult
+	photoIDs := make([]bson.ObjectID, len(servicePhotos.Photos))
+	for i, photo := range servicePhotos.Photos {
+		photoIDs[i] = photo.PhotoID
+	}
 
-		if err = cursor.Decode(&curRes); err != nil {
-			return nil, err
-		}
+	return photoIDs, servicePhotos.CoverPhotoID, nil
+}
 
-		rawResults = append(rawResults, &curRes)
+func (repo *mongoServiceRepository) DeleteServicePhoto(userID, serviceID, photoID string) error {
+	isOwner, err := repo.isUserServiceOwner(userID, serviceID)
+	if err != nil {
+		return err
 	}
 
-	if err = cursor.Err(); err != nil {
-		return nil, err
+	if !isOwner {
+		return ACCESS_DENIED
 	}
 
-	results := []*domain.ApiService{}
-	for _, res := range rawResults {
-		apiServ, err := res.ToApiService()
-		if err != nil {
-			return nil, err
+	serviceMongoID, err := bson.ObjectIDFromHex(serviceID)
+	if err != nil {
+		return BAD_SERVICE_ID
+	}
+
+	photoMongoID, err := bson.ObjectIDFromHex(photoID)
+	if err != nil {
+		return BAD_PHOTO_ID
+	}
+
+	update := bson.M{
+		"$pull": bson.M{"photos": bson.M{"_id": photoMongoID}},
+	}
+
+	res, err := repo.ServiceColl.UpdateByID(context.TODO(), serviceMongoID, update)
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount == 0 {
+		return NOT_FOUND
+	}
+
+	photoIDs, coverID, err := repo.getServicePhotoIDs(serviceMongoID)
+	if err != nil {
+		return err
+	}
+
+	if coverID != photoMongoID {
+		return nil
+	}
+
+	coverUpdate := bson.M{
+		"$unset": bson.M{"cover_photo": ""},
+	}
+	if len(photoIDs) != 0 {
+		coverUpdate = bson.M{
+			"$set": bson.M{"cover_photo": photoIDs[0]},
 		}
+	}
 
-		results = append(results, apiServ)
+	_, err = repo.ServiceColl.UpdateByID(context.TODO(), serviceMongoID, coverUpdate)
+	if err != nil {
+		return err
 	}
 
-	animalFilteredRes := []*domain.ApiService{}
-	if len(filters.Animals) != 0 {
-		IDsWithAnimals, err := repo.GetServiceIDsWithAnimals(filters.Animals)
+	return nil
+}
+
+func (repo *mongoServiceRepository) SetServiceCover(userID, serviceID, photoID string) error {
+	isOwner, err := repo.isUserServiceOwner(userID, serviceID)
+	if err != nil {
+		return err
+	}
+
+	if !isOwner {
+		return ACCESS_DENIED
+	}
+
+	serviceMongoID, err := bson.ObjectIDFromHex(serviceID)
+	if err != nil {
+		return BAD_SERVICE_ID
+	}
+
+	photoMongoID, err := bson.ObjectIDFromHex(photoID)
+	if err != nil {
+		return BAD_PHOTO_ID
+	}
+
+	filter := bson.M{
+		"_id":        serviceMongoID,
+		"photos._id": photoMongoID,
+	}
+
+	update := bson.M{
+		"$set": bson.M{"cover_photo": photoMongoID},
+	}
+
+	res, err := repo.ServiceColl.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return NOT_FOUND
+	}
+
+	return nil
+}
+
+func (repo *mongoServiceRepository) ReorderServicePhotos(userID, serviceID string, photoIDs []string) error {
+	isOwner, err := repo.isUserServiceOwner(userID, serviceID)
+	if err != nil {
+		return err
+	}
+
+	if !isOwner {
+		return ACCESS_DENIED
+	}
+
+	serviceMongoID, err := bson.ObjectIDFromHex(serviceID)
+	if err != nil {
+		return BAD_SERVICE_ID
+	}
+
+	var service struct {
+		Photos []domain.DBServicePhoto `bson:"photos"`
+	}
+
+	opt := options.FindOne().SetProjection(bson.M{"photos": 1, "_id": 0})
+	err = repo.ServiceColl.FindOne(context.TODO(), bson.M{"_id": serviceMongoID}, opt).Decode(&service)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return NOT_FOUND
+	} else if err != nil {
+		return err
+	}
+
+	if len(service.Photos) != len(photoIDs) {
+		return PHOTO_ORDER_MISMATCH
+	}
+
+	positions := make(map[bson.ObjectID]int, len(photoIDs))
+	for i, id := range photoIDs {
+		photoMongoID, err := bson.ObjectIDFromHex(id)
 		if err != nil {
-			return nil, err
+			return BAD_PHOTO_ID
 		}
 
-		IDsToLeave := make(map[string]struct{}, len(IDsWithAnimals))
-		for _, id := range IDsWithAnimals {
-			IDsToLeave[id] = struct{}{}
-		}
+		positions[photoMongoID] = i
+	}
+
+	if len(positions) != len(service.Photos) {
+		return PHOTO_ORDER_MISMATCH
+	}
 
-		for _, serv := range results {
-			if _, exists := IDsToLeave[serv.ServiceID]; exists {
-				animalFilteredRes = append(animalFilteredRes, serv)
-			}
+	currentIDs := make([]bson.ObjectID, len(service.Photos))
+	reordered := make([]domain.DBServicePhoto, len(service.Photos))
+	for i, photo := range service.Photos {
+		position, exists := positions[photo.PhotoID]
+		if !exists {
+			return PHOTO_ORDER_MISMATCH
 		}
 
-		return animalFilteredRes, nil
+		currentIDs[i] = photo.PhotoID
+		reordered[position] = photo
 	}
 
-	return results, nil
+	if len(reordered) == 0 {
+		return nil
+	}
+
+	// the photos are only replaced while they are still the ones read above,
+	// so a photo added or deleted in between is not lost or brought back
+	filter := bson.M{
+		"_id":        serviceMongoID,
+		"photos._id": bson.M{"$all": currentIDs},
+		"photos":     bson.M{"$size": len(currentIDs)},
+	}
+	update := bson.M{
+		"$set": bson.M{"photos": reordered},
+	}
+
+	res, err := repo.ServiceColl.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return PHOTO_ORDER_MISMATCH
+	}
+
+	return nil
 }
@@ -0,0 +1,152 @@
+package mongoTLC
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"mainService/pkg/animalTaxonomy"
+)
+
+// Services keep the animal types of their pets in the "animals" field, so
+// that search filters on them in the same query as on price and text. The
+// field has to be synced whenever the pets of a service change.
+
+// BackfillServiceAnimals fills the animals of the services stored before the
+// field was introduced. Services with no animals are synced again as well,
+// since an earlier backfill read the animal types from the wrong pet field
+// and left them empty.
+func BackfillServiceAnimals(db *mongo.Database) error {
+	return syncServiceAnimals(db, bson.M{"$or": bson.A{
+		bson.M{"animals": bson.M{"$exists": false}},
+		bson.M{"animals": bson.M{"$size": 0}},
+	}})
+}
+
+// syncServiceAnimals recomputes the animals of the services matching the
+// filter from their pets.
+func syncServiceAnimals(db *mongo.Database, filter bson.M) error {
+	opt := options.Find().SetProjection(bson.M{"pets": 1})
+	cursor, err := db.Collection("service").Find(context.TODO(), filter, opt)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	var services []struct {
+		ServiceID bson.ObjectID `bson:"_id"`
+		PetIDs    []bson.M      `bson:"pets"`
+	}
+	if err = cursor.All(context.TODO(), &services); err != nil {
+		return err
+	}
+
+	if len(services) == 0 {
+		return nil
+	}
+
+	petRefs := []bson.M{}
+	for _, service := range services {
+		petRefs = append(petRefs, service.PetIDs...)
+	}
+
+	petAnimals, err := getPetAnimals(db, petRefs)
+	if err != nil {
+		return err
+	}
+
+	models := make([]mongo.WriteModel, len(services))
+	for i, service := range services {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": service.ServiceID}).
+			SetUpdate(bson.M{"$set": bson.M{"animals": collectAnimals(service.PetIDs, petAnimals)}})
+	}
+
+	_, err = db.Collection("service").BulkWrite(context.TODO(), models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// getPetAnimals returns the animal types of the referenced pets by pet ID.
+func getPetAnimals(db *mongo.Database, petRefs []bson.M) (map[bson.ObjectID]string, error) {
+	petAnimals := map[bson.ObjectID]string{}
+
+	petMongoIDs := []bson.ObjectID{}
+	for _, ref := range petRefs {
+		if id, ok := ref["$id"].(bson.ObjectID); ok {
+			petMongoIDs = append(petMongoIDs, id)
+		}
+	}
+
+	if len(petMongoIDs) == 0 {
+		return petAnimals, nil
+	}
+
+	opt := options.Find().SetProjection(bson.M{"type": 1, "animal_id": 1})
+	cursor, err := db.Collection("pet").Find(context.TODO(), bson.M{"_id": bson.M{"$in": petMongoIDs}}, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var pet struct {
+			PetID        bson.ObjectID `bson:"_id"`
+			TypeOfAnimal string        `bson:"type"`
+			AnimalID     string        `bson:"animal_id"`
+		}
+
+		if err = cursor.Decode(&pet); err != nil {
+			return nil, err
+		}
+
+		petAnimals[pet.PetID] = pet.TypeOfAnimal
+		if pet.AnimalID != "" {
+			petAnimals[pet.PetID] = pet.AnimalID
+		}
+	}
+
+	return petAnimals, cursor.Err()
+}
+
+// NormalizeAnimal spells the animal type the way the animal collection and
+// the animals of services store it: the taxonomy ID of the species, so that
+// breeds count for their species, or the lowercased text for animals missing
+// from the taxonomy.
+func NormalizeAnimal(typeOfAnimal string) string {
+	if animal, found := animalTaxonomy.Canonical(typeOfAnimal); found {
+		return animal.SpeciesID()
+	}
+
+	return strings.TrimSpace(strings.ToLower(typeOfAnimal))
+}
+
+// CanonicalAnimal is the taxonomy ID pets keep for their animal type, the
+// breed one when the breed is known.
+func CanonicalAnimal(typeOfAnimal string) string {
+	if animal, found := animalTaxonomy.Canonical(typeOfAnimal); found {
+		return animal.ID
+	}
+
+	return strings.TrimSpace(strings.ToLower(typeOfAnimal))
+}
+
+// collectAnimals returns the sorted distinct animal types of the pets; never
+// nil, so that synced services always have the field.
+func collectAnimals(petRefs []bson.M, petAnimals map[bson.ObjectID]string) []string {
+	animals := []string{}
+	for _, ref := range petRefs {
+		id, _ := ref["$id"].(bson.ObjectID)
+		animal := NormalizeAnimal(petAnimals[id])
+		if animal != "" && !slices.Contains(animals, animal) {
+			animals = append(animals, animal)
+		}
+	}
+
+	slices.Sort(animals)
+
+	return animals
+}
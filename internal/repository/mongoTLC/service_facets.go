@@ -0,0 +1,117 @@
+package mongoTLC
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"mainService/internal/domain"
+)
+
+// PRICE_FACET_BOUNDARIES are the lower bounds of the price histogram buckets;
+// the last bucket has no upper bound.
+var PRICE_FACET_BOUNDARIES = []int32{0, 500, 1000, 2500, 5000, 10000}
+
+// UNRATED_FACET counts services without a rating, which is all of them until
+// services get rated.
+const UNRATED_FACET = "unrated"
+
+const priceAboveLastBoundary = "above"
+
+// facetStages computes the page and the facets of the matching services in
+// one $facet stage.
+func facetStages(pageStages bson.A) bson.M {
+	return bson.M{
+		"results": pageStages,
+		"animals": bson.A{
+			bson.D{{"$unwind", "$animals"}},
+			bson.D{{"$group", bson.M{"_id": "$animals", "count": bson.M{"$sum": 1}}}},
+			bson.D{{"$sort", bson.D{{"count", -1}, {"_id", 1}}}},
+		},
+		"roles": bson.A{
+			bson.D{{"$match", bson.M{"role": bson.M{"$exists": true}}}},
+			bson.D{{"$group", bson.M{"_id": "$role", "count": bson.M{"$sum": 1}}}},
+			bson.D{{"$sort", bson.D{{"count", -1}, {"_id", 1}}}},
+		},
+		"prices": bson.A{
+			bson.D{{"$bucket", bson.M{
+				// a zero price is not stored
+				"groupBy":    bson.M{"$ifNull": bson.A{"$price", 0}},
+				"boundaries": PRICE_FACET_BOUNDARIES,
+				"default":    priceAboveLastBoundary,
+				"output":     bson.M{"count": bson.M{"$sum": 1}},
+			}}},
+		},
+		"ratings": bson.A{
+			bson.D{{"$group", bson.M{
+				"_id":   bson.M{"$ifNull": bson.A{bson.M{"$floor": "$rating"}, UNRATED_FACET}},
+				"count": bson.M{"$sum": 1},
+			}}},
+			bson.D{{"$sort", bson.D{{"_id", -1}}}},
+		},
+		"total": bson.A{
+			bson.D{{"$count", "count"}},
+		},
+	}
+}
+
+type dbFacetCount struct {
+	Value any   `bson:"_id"`
+	Count int64 `bson:"count"`
+}
+
+type dbFacetedSearch struct {
+	Results []domain.DBServiceSerachResult `bson:"results"`
+	Animals []dbFacetCount                 `bson:"animals"`
+	Roles   []dbFacetCount                 `bson:"roles"`
+	Prices  []dbFacetCount                 `bson:"prices"`
+	Ratings []dbFacetCount                 `bson:"ratings"`
+	Total   []struct {
+		Count int64 `bson:"count"`
+	} `bson:"total"`
+}
+
+func (db *dbFacetedSearch) toApi() *domain.SearchFacets {
+	facets := &domain.SearchFacets{
+		Animals: facetCountsToApi(db.Animals),
+		Roles:   facetCountsToApi(db.Roles),
+		Ratings: facetCountsToApi(db.Ratings),
+	}
+
+	if len(db.Total) != 0 {
+		facets.Total = db.Total[0].Count
+	}
+
+	// every bucket is listed, empty ones included, so that the histogram
+	// keeps its shape
+	counts := map[string]int64{}
+	for _, bucket := range db.Prices {
+		counts[fmt.Sprint(bucket.Value)] = bucket.Count
+	}
+
+	last := len(PRICE_FACET_BOUNDARIES) - 1
+	facets.Prices = make([]*domain.PriceBucket, 0, len(PRICE_FACET_BOUNDARIES))
+	for i, lower := range PRICE_FACET_BOUNDARIES {
+		bucket := &domain.PriceBucket{Min: lower, Count: counts[fmt.Sprint(lower)]}
+
+		if i < last {
+			upper := PRICE_FACET_BOUNDARIES[i+1]
+			bucket.Max = &upper
+		} else {
+			bucket.Count += counts[priceAboveLastBoundary]
+		}
+
+		facets.Prices = append(facets.Prices, bucket)
+	}
+
+	return facets
+}
+
+func facetCountsToApi(dbCounts []dbFacetCount) []*domain.FacetCount {
+	counts := make([]*domain.FacetCount, 0, len(dbCounts))
+	for _, count := range dbCounts {
+		counts = append(counts, &domain.FacetCount{Value: fmt.Sprint(count.Value), Count: count.Count})
+	}
+
+	return counts
+}
@@ -0,0 +1,134 @@
+package mongoTLC
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"mainService/internal/domain"
+)
+
+// The weights of the feed signals, adding up to 1. Services have no location
+// yet, so distance is not among them, and the rating counts as 0 until
+// services get rated.
+const (
+	FEED_ANIMALS_WEIGHT    = 0.5
+	FEED_RATING_WEIGHT     = 0.15
+	FEED_RECENCY_WEIGHT    = 0.2
+	FEED_POPULARITY_WEIGHT = 0.15
+)
+
+const (
+	// FEED_RECENCY_DAYS is the age at which the recency of a service halves.
+	FEED_RECENCY_DAYS = 30
+	// FEED_POPULARITY_SCALE is the favorites count giving half of the
+	// popularity score.
+	FEED_POPULARITY_SCALE = 10
+	MAX_RATING            = 5
+)
+
+// GetFeed ranks the services of other users by the share of the animals they
+// are for, their rating, recency and popularity.
+func (repo *mongoServiceRepository) GetFeed(viewerID string, animals []string, page, pageSize int64) ([]*domain.FeedItem, error) {
+	filter := bson.M{"hidden": bson.M{"$ne": true}}
+	if viewerMongoID, err := bson.ObjectIDFromHex(viewerID); err == nil {
+		filter["owner.$id"] = bson.M{"$ne": viewerMongoID}
+	}
+
+	if animals == nil {
+		animals = []string{}
+	}
+
+	var animalsScore any = 0
+	if len(animals) != 0 {
+		animalsScore = bson.M{"$divide": bson.A{bson.M{"$size": "$matched_animals"}, len(animals)}}
+	}
+
+	favorites := bson.M{"$ifNull": bson.A{"$favorite_count", 0}}
+	recencyMillis := FEED_RECENCY_DAYS * (24 * time.Hour).Milliseconds()
+
+	if pageSize <= 0 {
+		pageSize = DEFAULT_SEARCH_PAGE_SIZE
+	}
+	pageSize = min(pageSize, MAX_SEARCH_PAGE_SIZE)
+
+	pipeline := mongo.Pipeline{
+		{{"$match", filter}},
+		{{"$addFields", bson.M{
+			"matched_animals": bson.M{"$setIntersection": bson.A{bson.M{"$ifNull": bson.A{"$animals", bson.A{}}}, animals}},
+		}}},
+		{{"$addFields", bson.M{
+			"feed_scores": bson.M{
+				"animals": animalsScore,
+				"rating":  bson.M{"$divide": bson.A{bson.M{"$ifNull": bson.A{"$rating", 0}}, MAX_RATING}},
+				"recency": bson.M{"$divide": bson.A{1, bson.M{"$add": bson.A{1, bson.M{"$divide": bson.A{
+					bson.M{"$subtract": bson.A{"$$NOW", bson.M{"$toDate": "$_id"}}},
+					recencyMillis,
+				}}}}}},
+				"popularity": bson.M{"$divide": bson.A{favorites, bson.M{"$add": bson.A{favorites, FEED_POPULARITY_SCALE}}}},
+			},
+		}}},
+		{{"$addFields", bson.M{
+			"feed_score": bson.M{"$add": bson.A{
+				bson.M{"$multiply": bson.A{"$feed_scores.animals", FEED_ANIMALS_WEIGHT}},
+				bson.M{"$multiply": bson.A{"$feed_scores.rating", FEED_RATING_WEIGHT}},
+				bson.M{"$multiply": bson.A{"$feed_scores.recency", FEED_RECENCY_WEIGHT}},
+				bson.M{"$multiply": bson.A{"$feed_scores.popularity", FEED_POPULARITY_WEIGHT}},
+			}},
+		}}},
+		{{"$sort", bson.D{{"feed_score", -1}, {"_id", -1}}}},
+		{{"$skip", max(page, 0) * pageSize}},
+		{{"$limit", pageSize}},
+		{{"$project", bson.M{"user_image": 0}}},
+		{{"$addFields", bson.M{"photos": coverPhotoOnly}}},
+	}
+
+	cursor, err := repo.ServiceColl.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var dbItems []*domain.DBFeedItem
+	if err = cursor.All(context.TODO(), &dbItems); err != nil {
+		return nil, err
+	}
+
+	items := make([]*domain.FeedItem, len(dbItems))
+	for i, dbItem := range dbItems {
+		service, err := dbItem.ToApiService()
+		if err != nil {
+			return nil, err
+		}
+
+		items[i] = &domain.FeedItem{
+			Service:        service,
+			Score:          dbItem.FeedScore,
+			Reason:         feedReason(dbItem.FeedScores),
+			MatchedAnimals: dbItem.MatchedAnimals,
+		}
+	}
+
+	return items, nil
+}
+
+// feedReason picks the signal that added the most to the score.
+func feedReason(scores domain.FeedScores) domain.FeedReason {
+	reason, best := domain.ReasonNew, scores.Recency*FEED_RECENCY_WEIGHT
+
+	if weighted := scores.Animals * FEED_ANIMALS_WEIGHT; weighted > best {
+		reason, best = domain.ReasonYourPets, weighted
+	}
+
+	if weighted := scores.Rating * FEED_RATING_WEIGHT; weighted > best {
+		reason, best = domain.ReasonTopRated, weighted
+	}
+
+	if weighted := scores.Popularity * FEED_POPULARITY_WEIGHT; weighted > best {
+		reason = domain.ReasonPopular
+	}
+
+	return reason
+}
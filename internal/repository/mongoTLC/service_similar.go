@@ -0,0 +1,219 @@
+package mongoTLC
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"mainService/internal/domain"
+	"mainService/pkg/fuzzyText"
+	"mainService/pkg/textLanguage"
+)
+
+// The weights of the similarity signals, adding up to 1.
+const (
+	SIMILAR_TEXT_WEIGHT    = 0.5
+	SIMILAR_ANIMALS_WEIGHT = 0.3
+	SIMILAR_PRICE_WEIGHT   = 0.2
+)
+
+const (
+	DEFAULT_SIMILAR_SERVICES = 6
+	MAX_SIMILAR_SERVICES     = 20
+	// SIMILAR_CANDIDATES is the number of services taken by the text index
+	// and by the animals each to be scored.
+	SIMILAR_CANDIDATES = 100
+	// SIMILAR_MAX_QUERY_WORDS limits the words of the service looked up in
+	// the text index, the title ones going first.
+	SIMILAR_MAX_QUERY_WORDS = 30
+)
+
+type similarCandidate struct {
+	domain.DBServiceSerachResult `bson:",inline"`
+	Animals                      []string `bson:"animals"`
+	Language                     string   `bson:"language"`
+}
+
+// GetSimilarServices finds the services of other users sharing words or
+// animals with the service and scores them by the overlap of the terms of
+// their texts, the overlap of their animals and how close their prices are.
+func (repo *mongoServiceRepository) GetSimilarServices(serviceID string, limit int64) ([]*domain.SimilarService, error) {
+	mongoID, err := bson.ObjectIDFromHex(serviceID)
+	if err != nil {
+		return nil, BAD_SERVICE_ID
+	}
+
+	source := new(similarCandidate)
+	err = repo.ServiceColl.FindOne(context.TODO(), bson.M{"_id": mongoID, "hidden": bson.M{"$ne": true}}).Decode(source)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, NOT_FOUND
+	} else if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DEFAULT_SIMILAR_SERVICES
+	}
+	limit = min(limit, MAX_SIMILAR_SERVICES)
+
+	filter := bson.M{
+		"_id":       bson.M{"$ne": mongoID},
+		"owner.$id": bson.M{"$ne": source.UserID["$id"]},
+		"hidden":    bson.M{"$ne": true},
+	}
+
+	candidates, err := repo.getTextCandidates(source, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	animalCandidates, err := repo.getAnimalCandidates(source, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[bson.ObjectID]struct{}, len(candidates))
+	for _, candidate := range candidates {
+		seen[candidate.ServiceID] = struct{}{}
+	}
+	for _, candidate := range animalCandidates {
+		if _, found := seen[candidate.ServiceID]; !found {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	sourceTerms := textLanguage.Terms(source.Title, source.Description)
+
+	similar := []*domain.SimilarService{}
+	for _, candidate := range candidates {
+		textScore := jaccard(sourceTerms, textLanguage.Terms(candidate.Title, candidate.Description))
+		animalsScore := jaccard(toSet(source.Animals), toSet(candidate.Animals))
+		if textScore == 0 && animalsScore == 0 {
+			continue
+		}
+
+		service, err := candidate.ToApiService()
+		if err != nil {
+			return nil, err
+		}
+
+		similar = append(similar, &domain.SimilarService{
+			Service: service,
+			Score: textScore*SIMILAR_TEXT_WEIGHT + animalsScore*SIMILAR_ANIMALS_WEIGHT +
+				priceCloseness(source.Price, candidate.Price)*SIMILAR_PRICE_WEIGHT,
+		})
+	}
+
+	slices.SortStableFunc(similar, func(a, b *domain.SimilarService) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	return similar[:min(int64(len(similar)), limit)], nil
+}
+
+func (repo *mongoServiceRepository) getTextCandidates(source *similarCandidate, filter bson.M) ([]*similarCandidate, error) {
+	words := fuzzyText.Words(source.Title + " " + source.Description)
+	if len(words) == 0 {
+		return []*similarCandidate{}, nil
+	}
+	words = words[:min(len(words), SIMILAR_MAX_QUERY_WORDS)]
+
+	language := source.Language
+	if language == "" {
+		language = string(textLanguage.Detect(source.Title, source.Description))
+	}
+
+	textFilter := bson.M{"$text": bson.M{"$search": strings.Join(words, " "), "$language": language}}
+	for key, value := range filter {
+		textFilter[key] = value
+	}
+
+	return repo.findCandidates(mongo.Pipeline{
+		{{"$match", textFilter}},
+		{{"$sort", bson.M{"score": bson.M{"$meta": "textScore"}}}},
+	})
+}
+
+// getAnimalCandidates takes the newest services for the same animals with
+// a price from half to twice the price of the source.
+func (repo *mongoServiceRepository) getAnimalCandidates(source *similarCandidate, filter bson.M) ([]*similarCandidate, error) {
+	if len(source.Animals) == 0 {
+		return []*similarCandidate{}, nil
+	}
+
+	animalFilter := bson.M{"animals": bson.M{"$in": source.Animals}}
+	if source.Price > 0 {
+		animalFilter["price"] = bson.M{"$gte": source.Price / 2, "$lte": source.Price * 2}
+	}
+	for key, value := range filter {
+		animalFilter[key] = value
+	}
+
+	return repo.findCandidates(mongo.Pipeline{
+		{{"$match", animalFilter}},
+		{{"$sort", bson.D{{"_id", -1}}}},
+	})
+}
+
+func (repo *mongoServiceRepository) findCandidates(pipeline mongo.Pipeline) ([]*similarCandidate, error) {
+	pipeline = append(pipeline,
+		bson.D{{"$limit", SIMILAR_CANDIDATES}},
+		bson.D{{"$project", bson.M{"user_image": 0, "title_words": 0, "title_trigrams": 0}}},
+		bson.D{{"$addFields", bson.M{"photos": coverPhotoOnly}}},
+	)
+
+	cursor, err := repo.ServiceColl.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	candidates := []*similarCandidate{}
+	if err = cursor.All(context.TODO(), &candidates); err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for term := range a {
+		if _, found := b[term]; found {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+
+	return set
+}
+
+// priceCloseness is the ratio of the lower price to the higher one.
+func priceCloseness(a, b int32) float64 {
+	if a == b {
+		return 1
+	}
+
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+
+	return float64(min(a, b)) / float64(max(a, b))
+}
@@ -0,0 +1,306 @@
+package mongoTLC
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"mainService/internal/domain"
+	"mainService/pkg/animalTaxonomy"
+	"mainService/pkg/fuzzyText"
+	"mainService/pkg/textLanguage"
+)
+
+const (
+	// FUZZY_FALLBACK_MIN_MATCHES is the number of text index matches below
+	// which the search also looks for titles with similar trigrams.
+	FUZZY_FALLBACK_MIN_MATCHES = 3
+	// FUZZY_MIN_SIMILARITY is the share of the trigrams of the query a title
+	// needs to have to be found by the fuzzy search.
+	FUZZY_MIN_SIMILARITY = 0.5
+	// textMatchScore ranks the text index matches above any fuzzy one.
+	textMatchScore = 2.0
+)
+
+const (
+	DEFAULT_SUGGESTIONS = 10
+	MAX_SUGGESTIONS     = 20
+)
+
+// The $text index only finds exact stems, so services keep the words of their
+// title for autocomplete and the trigrams of these words for typos.
+
+// BackfillServiceTitles fills the title words and trigrams of the services
+// stored before the fields were introduced.
+func BackfillServiceTitles(db *mongo.Database) error {
+	opt := options.Find().SetProjection(bson.M{"title": 1})
+	cursor, err := db.Collection("service").Find(context.TODO(), bson.M{"title_trigrams": bson.M{"$exists": false}}, opt)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	var services []struct {
+		ServiceID bson.ObjectID `bson:"_id"`
+		Title     string        `bson:"title"`
+	}
+	if err = cursor.All(context.TODO(), &services); err != nil {
+		return err
+	}
+
+	if len(services) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(services))
+	for i, service := range services {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": service.ServiceID}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"title_words":    fuzzyText.Words(service.Title),
+				"title_trigrams": fuzzyText.Trigrams(service.Title),
+			}})
+	}
+
+	_, err = db.Collection("service").BulkWrite(context.TODO(), models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// BackfillServiceLanguages detects the language of the services stored
+// without one, or of all the services when redetect is set.
+func BackfillServiceLanguages(db *mongo.Database, redetect bool) (int, error) {
+	filter := bson.M{"language": bson.M{"$exists": false}}
+	if redetect {
+		filter = bson.M{}
+	}
+
+	opt := options.Find().SetProjection(bson.M{"title": 1, "description": 1})
+	cursor, err := db.Collection("service").Find(context.TODO(), filter, opt)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	models := []mongo.WriteModel{}
+	for cursor.Next(context.TODO()) {
+		var service struct {
+			ServiceID   bson.ObjectID `bson:"_id"`
+			Title       string        `bson:"title"`
+			Description string        `bson:"description"`
+		}
+
+		if err = cursor.Decode(&service); err != nil {
+			return 0, err
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": service.ServiceID}).
+			SetUpdate(bson.M{"$set": bson.M{"language": textLanguage.Detect(service.Title, service.Description)}}))
+	}
+
+	if err = cursor.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	_, err = db.Collection("service").BulkWrite(context.TODO(), models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(models), nil
+}
+
+// textSearchStages matches the services by the text index and adds the
+// relevance score. When the index finds too few services, the ones with
+// similar title trigrams are matched as well, scored by similarity.
+func (repo *mongoServiceRepository) textSearchStages(queryString, language string, filter bson.M) (mongo.Pipeline, error) {
+	if language == "" {
+		language = string(textLanguage.Detect(queryString))
+	}
+
+	textFilter := bson.M{"$text": bson.M{"$search": queryString, "$language": language}}
+	for key, value := range filter {
+		textFilter[key] = value
+	}
+
+	queryTrigrams := fuzzyText.Trigrams(queryString)
+
+	textMatches := int64(0)
+	if len(queryTrigrams) != 0 {
+		var err error
+		opt := options.Count().SetLimit(FUZZY_FALLBACK_MIN_MATCHES)
+		textMatches, err = repo.ServiceColl.CountDocuments(context.TODO(), textFilter, opt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(queryTrigrams) == 0 || textMatches >= FUZZY_FALLBACK_MIN_MATCHES {
+		return mongo.Pipeline{
+			{{"$match", textFilter}},
+			{{"$addFields", bson.M{"score": bson.M{"$meta": "textScore"}}}},
+		}, nil
+	}
+
+	textMatchIDs := []bson.ObjectID{}
+	err := repo.ServiceColl.Distinct(context.TODO(), "_id", textFilter).Decode(&textMatchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	fuzzyFilter := bson.M{"$or": bson.A{
+		bson.M{"_id": bson.M{"$in": textMatchIDs}},
+		bson.M{"title_trigrams": bson.M{"$in": queryTrigrams}},
+	}}
+	for key, value := range filter {
+		fuzzyFilter[key] = value
+	}
+
+	return mongo.Pipeline{
+		{{"$match", fuzzyFilter}},
+		{{"$addFields", bson.M{"score": bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{"$_id", textMatchIDs}},
+			textMatchScore,
+			trigramSimilarity(queryTrigrams),
+		}}}}},
+		{{"$match", bson.M{"score": bson.M{"$gte": FUZZY_MIN_SIMILARITY}}}},
+	}, nil
+}
+
+// trigramSimilarity computes fuzzyText.Similarity of the query against the
+// title of the service.
+func trigramSimilarity(queryTrigrams []string) bson.M {
+	return bson.M{"$divide": bson.A{
+		bson.M{"$size": bson.M{"$setIntersection": bson.A{
+			bson.M{"$ifNull": bson.A{"$title_trigrams", bson.A{}}},
+			queryTrigrams,
+		}}},
+		len(queryTrigrams),
+	}}
+}
+
+// Autocomplete suggests the titles having the words typed so far, the last
+// one possibly unfinished, and the animals starting with the input. Titles
+// with similar trigrams fill the suggestions up when there are too few.
+func (repo *mongoServiceRepository) Autocomplete(input string, limit int64) (*domain.Suggestions, error) {
+	if limit <= 0 {
+		limit = DEFAULT_SUGGESTIONS
+	}
+	limit = min(limit, MAX_SUGGESTIONS)
+
+	suggestions := &domain.Suggestions{Titles: []string{}, Animals: []string{}}
+
+	tokens := fuzzyText.Tokens(input)
+	if len(tokens) == 0 {
+		return suggestions, nil
+	}
+
+	words, prefix := tokens[:len(tokens)-1], tokens[len(tokens)-1]
+	conditions := bson.A{bson.M{"title_words": bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}}}
+	if len(words) != 0 {
+		conditions = append(conditions, bson.M{"title_words": bson.M{"$all": words}})
+	}
+
+	titles, err := repo.suggestTitles(mongo.Pipeline{
+		{{"$match", bson.M{"hidden": bson.M{"$ne": true}, "$and": conditions}}},
+		{{"$group", bson.M{"_id": "$title", "score": bson.M{"$sum": 1}}}},
+		{{"$sort", bson.D{{"score", -1}, {"_id", 1}}}},
+		{{"$limit", limit}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	suggestions.Titles = titles
+
+	queryTrigrams := fuzzyText.Trigrams(input)
+	if int64(len(titles)) < limit && len(queryTrigrams) != 0 {
+		similarTitles, err := repo.suggestTitles(mongo.Pipeline{
+			{{"$match", bson.M{"hidden": bson.M{"$ne": true}, "title_trigrams": bson.M{"$in": queryTrigrams}}}},
+			{{"$addFields", bson.M{"score": trigramSimilarity(queryTrigrams)}}},
+			{{"$match", bson.M{"score": bson.M{"$gte": FUZZY_MIN_SIMILARITY}}}},
+			{{"$group", bson.M{"_id": "$title", "score": bson.M{"$max": "$score"}}}},
+			{{"$sort", bson.D{{"score", -1}, {"_id", 1}}}},
+			{{"$limit", limit}},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, title := range similarTitles {
+			if int64(len(suggestions.Titles)) < limit && !slices.Contains(suggestions.Titles, title) {
+				suggestions.Titles = append(suggestions.Titles, title)
+			}
+		}
+	}
+
+	// the animal collection keeps taxonomy IDs, so the input is looked up
+	// among the names of the animals in every language as well
+	animalPrefix := strings.TrimSpace(strings.ToLower(strings.Join(strings.Fields(input), " ")))
+	animalIDs := []string{}
+	for _, animal := range animalTaxonomy.Lookup(input, "", 0) {
+		if !slices.Contains(animalIDs, animal.SpeciesID()) {
+			animalIDs = append(animalIDs, animal.SpeciesID())
+		}
+	}
+
+	opt := options.Find().
+		SetSort(bson.D{{"count", -1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"type_of_animal": 1, "_id": 0})
+
+	animalFilter := bson.M{"$or": bson.A{
+		bson.M{"type_of_animal": bson.M{"$regex": "^" + regexp.QuoteMeta(animalPrefix)}},
+		bson.M{"type_of_animal": bson.M{"$in": animalIDs}},
+	}}
+
+	cursor, err := repo.AnimalColl.Find(context.TODO(), animalFilter, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var animal struct {
+			TypeOfAnimal string `bson:"type_of_animal"`
+		}
+
+		if err = cursor.Decode(&animal); err != nil {
+			return nil, err
+		}
+
+		suggestions.Animals = append(suggestions.Animals, animal.TypeOfAnimal)
+	}
+
+	return suggestions, cursor.Err()
+}
+
+func (repo *mongoServiceRepository) suggestTitles(pipeline mongo.Pipeline) ([]string, error) {
+	cursor, err := repo.ServiceColl.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var results []struct {
+		Title string `bson:"_id"`
+	}
+	if err = cursor.All(context.TODO(), &results); err != nil {
+		return nil, err
+	}
+
+	titles := make([]string, len(results))
+	for i, result := range results {
+		titles[i] = result.Title
+	}
+
+	return titles, nil
+}
@@ -0,0 +1,127 @@
+package mongoTLC
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"mainService/internal/domain"
+)
+
+type ISwearWordRepository interface {
+	AddSwearWord(moderatorID string, word *domain.ApiSwearWord) error
+	DeleteSwearWord(word string, allowed bool) error
+	GetSwearWords() ([]*domain.DBSwearWord, error)
+	GetSwearWordsVersion() (string, error)
+}
+
+type mongoSwearWordRepository struct {
+	DB   *mongo.Database
+	Coll *mongo.Collection
+}
+
+func NewMongoSwearWordRepository(db *mongo.Database) ISwearWordRepository {
+	return &mongoSwearWordRepository{
+		DB:   db,
+		Coll: db.Collection("swear_word"),
+	}
+}
+
+func (repo *mongoSwearWordRepository) AddSwearWord(moderatorID string, word *domain.ApiSwearWord) error {
+	moderatorMongoID, err := bson.ObjectIDFromHex(moderatorID)
+	if err != nil {
+		return BAD_USER_ID
+	}
+
+	filter := bson.M{
+		"word":    strings.ToLower(word.Word),
+		"allowed": word.Allowed,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"language":   word.Language,
+			"severity":   word.Severity,
+			"moderator":  moderatorMongoID,
+			"updated_at": time.Now(),
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err = repo.Coll.UpdateOne(context.TODO(), filter, update, opts)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo *mongoSwearWordRepository) DeleteSwearWord(word string, allowed bool) error {
+	filter := bson.M{
+		"word":    strings.ToLower(word),
+		"allowed": allowed,
+	}
+
+	res, err := repo.Coll.DeleteOne(context.TODO(), filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return NOT_FOUND
+	}
+
+	return nil
+}
+
+func (repo *mongoSwearWordRepository) GetSwearWords() ([]*domain.DBSwearWord, error) {
+	opt := options.Find().SetSort(bson.D{{"word", 1}})
+	cursor, err := repo.Coll.Find(context.TODO(), bson.M{}, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var words []*domain.DBSwearWord
+	if err = cursor.All(context.TODO(), &words); err != nil {
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// GetSwearWordsVersion returns a string that changes whenever a word is added,
+// updated or deleted.
+func (repo *mongoSwearWordRepository) GetSwearWordsVersion() (string, error) {
+	pipeline := mongo.Pipeline{
+		{{"$group", bson.M{
+			"_id":          nil,
+			"count":        bson.M{"$sum": 1},
+			"last_updated": bson.M{"$max": "$updated_at"},
+		}}},
+	}
+
+	cursor, err := repo.Coll.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return "", err
+	}
+	defer cursor.Close(context.TODO())
+
+	var results []struct {
+		Count       int64     `bson:"count"`
+		LastUpdated time.Time `bson:"last_updated"`
+	}
+	if err = cursor.All(context.TODO(), &results); err != nil {
+		return "", err
+	}
+
+	if len(results) == 0 {
+		return "0", nil
+	}
+
+	return fmt.Sprintf("%d:%d", results[0].Count, results[0].LastUpdated.UnixNano()), nil
+}
@@ -3,6 +3,7 @@ package mongoTLC
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -20,12 +21,15 @@ type IUserRepository interface {
 	AddUser(newUser *domain.ApiUserInfo) (string, error)
 	UpdateUser(userID string, updInfo *domain.ApiUserUpdate) error
 	GetUserInfo(userID string) (*domain.ApiUserInfo, error)
+	GetUserInfoIncludingHidden(userID string) (*domain.ApiUserInfo, error)
 	GetAvatarBytes(userID string) ([]byte, error)
 	AddPet(userID string, pet *domain.ApiPetInfo) (string, error)
 	DeletePet(userID, petID string) error
 	UpdatePet(userID, petID string, updInfo *domain.ApiPetUpdate) error
 	GetUserPets(userID string) ([]string, error)
 	GetUserServices(userID string) ([]string, error)
+	SuspendUser(userID string, until time.Time) error
+	GetSuspendedUntil(userID string) (time.Time, error)
 }
 
 type mongoUserRepository struct {
@@ -148,13 +152,24 @@ func (repo *mongoUserRepository) UpdateUser(userID string, updInfo *domain.ApiUs
 }
 
 func (repo *mongoUserRepository) GetUserInfo(userID string) (*domain.ApiUserInfo, error) {
+	return repo.getUserInfo(userID, bson.M{"hidden": bson.M{"$ne": true}})
+}
+
+// GetUserInfoIncludingHidden is for users looking at their own profile,
+// which they can still see when moderators have hidden it.
+func (repo *mongoUserRepository) GetUserInfoIncludingHidden(userID string) (*domain.ApiUserInfo, error) {
+	return repo.getUserInfo(userID, bson.M{})
+}
+
+func (repo *mongoUserRepository) getUserInfo(userID string, filter bson.M) (*domain.ApiUserInfo, error) {
 	mongoID, err := bson.ObjectIDFromHex(userID)
 	if err != nil {
 		return nil, BAD_USER_ID
 	}
+	filter["_id"] = mongoID
 
 	dbInfo := new(domain.DBUserInfo)
-	err = repo.Coll.FindOne(context.TODO(), bson.M{"_id": mongoID}).Decode(dbInfo)
+	err = repo.Coll.FindOne(context.TODO(), filter).Decode(dbInfo)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, NOT_FOUND
 	} else if err != nil {
@@ -176,6 +191,7 @@ func (repo *mongoUserRepository) AddPet(userID string, pet *domain.ApiPetInfo) (
 	if err != nil {
 		return "", err
 	}
+	dbInfo.AnimalID = CanonicalAnimal(pet.TypeOfAnimal)
 
 	res, err := pet_col.InsertOne(context.TODO(), *dbInfo)
 	if err != nil {
@@ -259,6 +275,13 @@ func (repo *mongoUserRepository) DeletePet(userID, petID string) error {
 		"$pull": bson.M{"pets": petDBRef},
 	}
 
+	// the services are looked up before the pet is pulled out of them
+	var affectedServices []bson.ObjectID
+	err = repo.DB.Collection("service").Distinct(context.TODO(), "_id", filter).Decode(&affectedServices)
+	if err != nil {
+		return err
+	}
+
 	res, err := repo.DB.Collection("service").UpdateMany(context.TODO(), filter, updateServices)
 	if err != nil {
 		return err
@@ -267,6 +290,11 @@ func (repo *mongoUserRepository) DeletePet(userID, petID string) error {
 		return NOT_FOUND
 	}
 
+	err = syncServiceAnimals(repo.DB, bson.M{"_id": bson.M{"$in": affectedServices}})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -289,6 +317,7 @@ func (repo *mongoUserRepository) UpdatePet(userID, petID string, updInfo *domain
 	if err != nil {
 		return err
 	}
+	dbUpd.AnimalID = CanonicalAnimal(updInfo.TypeOfAnimal)
 
 	update := bson.M{
 		"$set": dbUpd,
@@ -299,6 +328,13 @@ func (repo *mongoUserRepository) UpdatePet(userID, petID string, updInfo *domain
 		return err
 	}
 
+	if updInfo.TypeOfAnimal != "" {
+		err = syncServiceAnimals(repo.DB, bson.M{"pets.$id": petMongoID})
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -412,3 +448,44 @@ func (repo *mongoUserRepository) GetUserServices(userID string) ([]string, error
 
 	return strServiceIDs, nil
 }
+
+func (repo *mongoUserRepository) SuspendUser(userID string, until time.Time) error {
+	mongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return BAD_USER_ID
+	}
+
+	res, err := repo.Coll.UpdateByID(context.TODO(), mongoID, bson.M{"$set": bson.M{"suspended_until": until}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return NOT_FOUND
+	}
+
+	return nil
+}
+
+// GetSuspendedUntil returns the zero time for users that have never been
+// suspended.
+func (repo *mongoUserRepository) GetSuspendedUntil(userID string) (time.Time, error) {
+	mongoID, err := bson.ObjectIDFromHex(userID)
+	if err != nil {
+		return time.Time{}, BAD_USER_ID
+	}
+
+	var suspension struct {
+		Until time.Time `bson:"suspended_until"`
+	}
+
+	opt := options.FindOne().SetProjection(bson.M{"suspended_until": 1, "_id": 0})
+	err = repo.Coll.FindOne(context.TODO(), bson.M{"_id": mongoID}, opt).Decode(&suspension)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, NOT_FOUND
+	} else if err != nil {
+		return time.Time{}, err
+	}
+
+	return suspension.Until, nil
+}
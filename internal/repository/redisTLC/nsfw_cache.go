@@ -0,0 +1,80 @@
+package redisTLC
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"mainService/pkg/nsfwFilter"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+type cachedVerdict struct {
+	IsSafe     bool    `json:"is_safe"`
+	Confidence float64 `json:"confidence"`
+	Code       int     `json:"code"`
+}
+
+type redisVerdictCache struct {
+	cacheStorage *redis.Pool
+	ttl          time.Duration
+}
+
+func NewRedisVerdictCache(conn *redis.Pool, ttl time.Duration) nsfwFilter.VerdictCache {
+	return &redisVerdictCache{
+		cacheStorage: conn,
+		ttl:          ttl,
+	}
+}
+
+func (c *redisVerdictCache) Get(key string) (nsfwFilter.Inference, bool, error) {
+	connection := c.cacheStorage.Get()
+	defer connection.Close()
+
+	cacheKey := "nsfw_verdicts:" + key
+
+	rawVerdict, err := redis.Bytes(connection.Do("GET", cacheKey))
+	if errors.Is(err, redis.ErrNil) {
+		return nsfwFilter.Inference{}, false, nil
+	} else if err != nil {
+		return nsfwFilter.Inference{}, false, err
+	}
+
+	verdict := cachedVerdict{}
+	err = json.Unmarshal(rawVerdict, &verdict)
+	if err != nil {
+		return nsfwFilter.Inference{}, false, err
+	}
+
+	inf := nsfwFilter.Inference{
+		IsSafe:     verdict.IsSafe,
+		Confidence: verdict.Confidence,
+		Code:       verdict.Code,
+	}
+
+	return inf, true, nil
+}
+
+func (c *redisVerdictCache) Set(key string, inf nsfwFilter.Inference) error {
+	connection := c.cacheStorage.Get()
+	defer connection.Close()
+
+	cacheKey := "nsfw_verdicts:" + key
+
+	rawVerdict, err := json.Marshal(cachedVerdict{
+		IsSafe:     inf.IsSafe,
+		Confidence: inf.Confidence,
+		Code:       inf.Code,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = redis.String(connection.Do("SET", cacheKey, rawVerdict, "EX", int64(c.ttl.Seconds())))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -7,6 +7,25 @@ var (
 	INVALID_ROLE             = fmt.Errorf("invalid role specified: must be either 'slave' or 'master'")
 	EMPTY_SEARCH_STRING      = fmt.Errorf("an empty search string has been specified")
 	INVALID_PRICE_RANGE      = fmt.Errorf("you have specified invalid price range: min and max prices must non-negative; min price must be less or equal to max price")
+	INVALID_SEARCH_SORT      = fmt.Errorf("invalid sort: must be 'relevance', 'newest', 'price_asc' or 'price_desc'")
+	INVALID_SEARCH_PAGE      = fmt.Errorf("page and page size must be non-negative")
+	INVALID_SEARCH_LANGUAGE  = fmt.Errorf("invalid search language: must be 'russian', 'english' or 'none'")
 	EMPTY_TITLE              = fmt.Errorf("empty title not allowed")
 	POSITIVE_NUMBER_REQUIRED = fmt.Errorf("positive number required")
+	EMPTY_IMAGE              = fmt.Errorf("image must be non-empty")
+	NOT_A_MODERATOR          = fmt.Errorf("this action is available to moderators only")
+	BAD_REPORT_TARGET        = fmt.Errorf("invalid report target: must be 'user', 'pet' or 'service'")
+	BAD_REPORT_REASON        = fmt.Errorf("invalid report reason: must be 'scam', 'abuse', 'inappropriate_content', 'spam' or 'other'")
+	BAD_CASE_ACTION          = fmt.Errorf("invalid case action: must be 'hide', 'unhide', 'warn', 'suspend' or 'dismiss'")
+	CASE_CLOSED              = fmt.Errorf("the moderation case is already closed")
+	USER_SUSPENDED           = fmt.Errorf("your account is suspended")
+	EMPTY_SAVED_SEARCH       = fmt.Errorf("a saved search needs a query string, a price range or animals")
+	BAD_EMAIL                = fmt.Errorf("invalid e-mail address")
+	EMAILS_DISABLED          = fmt.Errorf("e-mails are turned off on this server")
+	TOO_MANY_SAVED_SEARCHES  = fmt.Errorf("you already have the maximum number of saved searches")
+	SEARCH_ALERTS_QUEUE_FULL = fmt.Errorf("saved search alerts queue is full")
+	BAD_FAVORITE_TARGET      = fmt.Errorf("invalid favorite target: must be 'service' or 'provider'")
+	OWN_FAVORITE             = fmt.Errorf("you cannot add yourself or your own services to favorites")
+	BAD_ANIMAL_LANGUAGE      = fmt.Errorf("invalid language: must be 'en' or 'ru'")
+	BAD_TEXT_POLICY          = fmt.Errorf("invalid text policy: expected comma separated 'swears:action' and 'spam:action' with action 'reject', 'mask' or 'flag'")
 )
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+)
+
+type IFavoriteUsecase interface {
+	AddFavorite(userID string, favorite *domain.ApiFavorite) error
+	DeleteFavorite(userID string, favorite *domain.ApiFavorite) error
+	GetFavoriteServices(userID string) ([]*domain.ApiService, error)
+	GetFavoriteProviders(userID string) ([]*domain.ApiUserInfo, error)
+}
+
+type FavoriteUsecase struct {
+	favoriteRepo mongoTLC.IFavoriteRepository
+	serviceRepo  mongoTLC.IServiceRepository
+	userRepo     mongoTLC.IUserRepository
+}
+
+func NewFavoriteUsecase(
+	favoriteRepository mongoTLC.IFavoriteRepository,
+	serviceRepository mongoTLC.IServiceRepository,
+	userRepository mongoTLC.IUserRepository,
+) IFavoriteUsecase {
+	return &FavoriteUsecase{
+		favoriteRepo: favoriteRepository,
+		serviceRepo:  serviceRepository,
+		userRepo:     userRepository,
+	}
+}
+
+func (ucase *FavoriteUsecase) AddFavorite(userID string, favorite *domain.ApiFavorite) error {
+	if !domain.IsFavoriteTarget(favorite.Target) {
+		return BAD_FAVORITE_TARGET
+	}
+
+	ownerID := favorite.TargetID
+	if favorite.Target == domain.FavoriteService {
+		service, err := ucase.serviceRepo.GetServiceByID(favorite.TargetID)
+		if err != nil {
+			return err
+		}
+
+		ownerID = service.UserID
+	} else {
+		_, err := ucase.userRepo.GetUserInfo(favorite.TargetID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if ownerID == userID {
+		return OWN_FAVORITE
+	}
+
+	return ucase.favoriteRepo.AddFavorite(userID, favorite)
+}
+
+func (ucase *FavoriteUsecase) DeleteFavorite(userID string, favorite *domain.ApiFavorite) error {
+	if !domain.IsFavoriteTarget(favorite.Target) {
+		return BAD_FAVORITE_TARGET
+	}
+
+	return ucase.favoriteRepo.DeleteFavorite(userID, favorite)
+}
+
+// GetFavoriteServices lists the favorite services, the latest added first.
+// Hidden services are left out until they are back.
+func (ucase *FavoriteUsecase) GetFavoriteServices(userID string) ([]*domain.ApiService, error) {
+	serviceIDs, err := ucase.favoriteRepo.GetFavoriteIDs(userID, domain.FavoriteService)
+	if err != nil || len(serviceIDs) == 0 {
+		return []*domain.ApiService{}, err
+	}
+
+	found, err := ucase.serviceRepo.GetServicesByIDs(serviceIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*domain.ApiService, len(found))
+	for _, serv := range found {
+		byID[serv.ServiceID] = serv
+	}
+
+	services := make([]*domain.ApiService, 0, len(found))
+	for _, serviceID := range serviceIDs {
+		serv, ok := byID[serviceID]
+		if !ok {
+			continue
+		}
+
+		if len(serv.PetIDs) == 0 {
+			serv.PetIDs = []string{}
+		}
+
+		serv.Photos = nil
+		serv.IsFavorite = true
+
+		avatar, err := ucase.userRepo.GetAvatarBytes(serv.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		serv.UserImage = base64.StdEncoding.EncodeToString(avatar)
+		services = append(services, serv)
+	}
+
+	return services, nil
+}
+
+// GetFavoriteProviders lists the favorite providers without their background
+// images, the latest added first.
+func (ucase *FavoriteUsecase) GetFavoriteProviders(userID string) ([]*domain.ApiUserInfo, error) {
+	providerIDs, err := ucase.favoriteRepo.GetFavoriteIDs(userID, domain.FavoriteProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make([]*domain.ApiUserInfo, 0, len(providerIDs))
+	for _, providerID := range providerIDs {
+		provider, err := ucase.userRepo.GetUserInfo(providerID)
+		if errors.Is(err, mongoTLC.NOT_FOUND) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		provider.UserBackImage = ""
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+
+	"mainService/configs"
+	"mainService/internal/repository/mongoTLC"
+	"mainService/pkg/imageHash"
+	"mainService/pkg/serverErrors"
+)
+
+// blockedHashesRefresh is how long the blocked hashes are kept in memory, so
+// an image banned by a moderator is rejected by the other blocklists after
+// this delay at most.
+const blockedHashesRefresh = time.Minute
+
+type imageBlocklist struct {
+	imageHashRepo mongoTLC.IImageHashRepository
+
+	blocked         []uint64
+	blockedLoadedAt time.Time
+	blockedMu       sync.Mutex
+}
+
+func newImageBlocklist(imageHashRepository mongoTLC.IImageHashRepository) *imageBlocklist {
+	return &imageBlocklist{
+		imageHashRepo: imageHashRepository,
+	}
+}
+
+func hashBase64Image(base64Image string) (uint64, error) {
+	imageBytes, err := base64.StdEncoding.DecodeString(base64Image)
+	if err != nil {
+		return 0, err
+	}
+
+	return imageHash.DHash(imageBytes)
+}
+
+// Check hashes the images and rejects them if any is a near-duplicate of a
+// banned one, so that known scam pictures never reach the NSFW worker.
+// Images in formats the hash can't decode, like WebP, are left to the NSFW
+// worker alone and get a nil hash.
+func (bl *imageBlocklist) Check(base64Images ...string) ([]*uint64, error) {
+	hashes := make([]*uint64, len(base64Images))
+	for i, image := range base64Images {
+		hash, err := hashBase64Image(image)
+		if errors.Is(err, imageHash.DECODE_ERR) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		hashes[i] = &hash
+	}
+
+	blockedHashes, err := bl.getBlockedHashes()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hash := range hashes {
+		if hash == nil {
+			continue
+		}
+
+		for _, blocked := range blockedHashes {
+			if imageHash.Distance(*hash, blocked) <= configs.ImageHashThreshold {
+				err = bl.imageHashRepo.IncrementBlockedHits(blocked)
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, serverErrors.BANNED_IMAGE_ERROR
+			}
+		}
+	}
+
+	return hashes, nil
+}
+
+// getBlockedHashes reads the blocked hashes again once the ones in memory are
+// older than blockedHashesRefresh.
+func (bl *imageBlocklist) getBlockedHashes() ([]uint64, error) {
+	bl.blockedMu.Lock()
+	defer bl.blockedMu.Unlock()
+
+	if bl.blocked != nil && time.Since(bl.blockedLoadedAt) < blockedHashesRefresh {
+		return bl.blocked, nil
+	}
+
+	blocked, err := bl.imageHashRepo.GetBlockedHashes()
+	if err != nil {
+		return nil, err
+	}
+
+	bl.blocked = blocked
+	bl.blockedLoadedAt = time.Now()
+	return blocked, nil
+}
+
+func (bl *imageBlocklist) Register(hashes []uint64) error {
+	for _, hash := range hashes {
+		err := bl.imageHashRepo.RegisterHash(hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
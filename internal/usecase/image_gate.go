@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"mainService/configs"
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+	"mainService/pkg/nsfwFilter"
+	"mainService/pkg/serverErrors"
+)
+
+const actionQueue nsfwFilter.Action = "queue"
+
+var nsfwRejectionErrors = map[domain.ImageTarget]error{
+	domain.TargetAvatar:       serverErrors.NSFW_CONTENT_AVATAR_ERROR,
+	domain.TargetBackground:   serverErrors.NSFW_CONTENT_BACK_IMAGE_ERROR,
+	domain.TargetPetAvatar:    serverErrors.NSFW_CONTENT_AVATAR_ERROR,
+	domain.TargetServicePhoto: serverErrors.NSFW_CONTENT_SERVICE_PHOTO_ERROR,
+}
+
+type imageField struct {
+	target domain.ImageTarget
+	value  *string
+}
+
+type imageDecision struct {
+	upload      *domain.ImageUpload
+	action      nsfwFilter.Action
+	probability float64
+	// hash is nil for images the blocklist can't hash.
+	hash *uint64
+}
+
+// imageGate runs every uploaded image through the blocklist and the NSFW
+// policy of its context before the entity it belongs to is written.
+type imageGate struct {
+	imageModerator nsfwFilter.ImageModerator
+	blocklist      *imageBlocklist
+	queue          *ImageModerationQueue
+}
+
+func newImageGate(
+	imageHashRepository mongoTLC.IImageHashRepository,
+	imageModerator nsfwFilter.ImageModerator,
+	moderationQueue *ImageModerationQueue,
+) *imageGate {
+	return &imageGate{
+		imageModerator: imageModerator,
+		blocklist:      newImageBlocklist(imageHashRepository),
+		queue:          moderationQueue,
+	}
+}
+
+// Check classifies the non-empty fields and clears the ones that must not be
+// published right away: hidden images, or every image in the async mode.
+// The returned decisions are to be passed to Track once the entity exists.
+func (g *imageGate) Check(fields ...imageField) ([]*imageDecision, error) {
+	decisions := []*imageDecision{}
+	images := []string{}
+	withheld := []*string{}
+
+	for _, field := range fields {
+		if *field.value == "" {
+			continue
+		}
+
+		decisions = append(decisions, &imageDecision{
+			upload: &domain.ImageUpload{Target: field.target, Image: *field.value},
+		})
+		images = append(images, *field.value)
+		withheld = append(withheld, field.value)
+	}
+
+	if len(images) == 0 {
+		return decisions, nil
+	}
+
+	hashes, err := g.blocklist.Check(images...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, decision := range decisions {
+		decision.hash = hashes[i]
+	}
+
+	if configs.AsyncModeration {
+		for i, decision := range decisions {
+			decision.action = actionQueue
+			*withheld[i] = ""
+		}
+
+		return decisions, nil
+	}
+
+	results := g.imageModerator.RunInParallel(images...)
+
+	for i, decision := range decisions {
+		if results[i].ProcessingErr != nil {
+			return nil, results[i].ProcessingErr
+		}
+
+		decision.probability = nsfwFilter.NSFWProbability(results[i].Inf)
+		decision.action = g.queue.decide(decision.upload.Target, results[i].Inf)
+
+		switch decision.action {
+		case nsfwFilter.ActionReject:
+			return nil, nsfwRejectionErrors[decision.upload.Target]
+		case nsfwFilter.ActionHide:
+			*withheld[i] = ""
+		}
+	}
+
+	return decisions, nil
+}
+
+// Track hands withheld and flagged images over to the moderation queue and
+// returns their IDs in the queue, empty for images published without review.
+// The hashes of the published images are registered here rather than in
+// Check, so that uploads failing afterwards aren't counted.
+func (g *imageGate) Track(ownerID, targetID string, decisions []*imageDecision) ([]string, error) {
+	imageIDs := make([]string, len(decisions))
+	publishedHashes := []uint64{}
+	for i, decision := range decisions {
+		decision.upload.TargetID = targetID
+
+		var err error
+		switch decision.action {
+		case actionQueue:
+			var ids []string
+			ids, err = g.queue.Submit(ownerID, decision.upload)
+			if err == nil {
+				imageIDs[i] = ids[0]
+			}
+		case nsfwFilter.ActionHide, nsfwFilter.ActionFlag:
+			imageIDs[i], err = g.queue.Track(ownerID, decision.upload, decision.action, decision.probability)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		published := decision.action == nsfwFilter.ActionAllow || decision.action == nsfwFilter.ActionFlag
+		if published && decision.hash != nil {
+			publishedHashes = append(publishedHashes, *decision.hash)
+		}
+	}
+
+	err := g.blocklist.Register(publishedHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return imageIDs, nil
+}
@@ -0,0 +1,339 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+	"mainService/pkg/imageHash"
+	"mainService/pkg/nsfwFilter"
+)
+
+const moderationRetryDelay = 30 * time.Second
+
+// maxModerationAttempts is how many times an image is classified before it
+// is left to the moderators.
+const maxModerationAttempts = 5
+
+var imageTargetNames = map[domain.ImageTarget]string{
+	domain.TargetAvatar:       "avatar",
+	domain.TargetBackground:   "background image",
+	domain.TargetPetAvatar:    "pet's avatar",
+	domain.TargetServicePhoto: "service photo",
+}
+
+var defaultNSFWPolicy = nsfwFilter.Policy{
+	Bands:     []nsfwFilter.Band{{UpTo: 0.5, Action: nsfwFilter.ActionAllow}},
+	Otherwise: nsfwFilter.ActionReject,
+}
+
+// ImageModerationQueue keeps track of images that are waiting for a verdict or
+// a moderator review. Images are published on their target only once approved,
+// so until then nobody but the owner can see them.
+type ImageModerationQueue struct {
+	pendingRepo      mongoTLC.IPendingImageRepository
+	notificationRepo mongoTLC.INotificationRepository
+	imageModerator   nsfwFilter.ImageModerator
+	blocklist        *imageBlocklist
+	policies         map[domain.ImageTarget]nsfwFilter.Policy
+	// onError reports the errors of the background work.
+	onError func(err error)
+
+	// attempts counts the failed classifications of the images being retried.
+	attempts   map[string]int
+	attemptsMu sync.Mutex
+
+	jobs   chan string
+	closed chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewImageModerationQueue(
+	pendingImageRepository mongoTLC.IPendingImageRepository,
+	notificationRepository mongoTLC.INotificationRepository,
+	imageHashRepository mongoTLC.IImageHashRepository,
+	imageModerator nsfwFilter.ImageModerator,
+	policies map[domain.ImageTarget]nsfwFilter.Policy,
+	onError func(err error),
+) *ImageModerationQueue {
+	return &ImageModerationQueue{
+		pendingRepo:      pendingImageRepository,
+		notificationRepo: notificationRepository,
+		imageModerator:   imageModerator,
+		blocklist:        newImageBlocklist(imageHashRepository),
+		policies:         policies,
+		onError:          onError,
+		attempts:         map[string]int{},
+		jobs:             make(chan string, 256),
+		closed:           make(chan struct{}),
+	}
+}
+
+// Start launches the workers and re-queues images that were still waiting
+// for a verdict when the server was stopped.
+func (q *ImageModerationQueue) Start(workers int) error {
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+
+	leftovers, err := q.pendingRepo.GetImagesByStatus(domain.StatusPending)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range leftovers {
+		q.enqueue(image.ImageID.Hex())
+	}
+
+	return nil
+}
+
+func (q *ImageModerationQueue) Close() {
+	close(q.closed)
+	q.wg.Wait()
+}
+
+func (q *ImageModerationQueue) enqueue(imageID string) {
+	select {
+	case q.jobs <- imageID:
+	default:
+		go func() {
+			select {
+			case q.jobs <- imageID:
+			case <-q.closed:
+			}
+		}()
+	}
+}
+
+func (q *ImageModerationQueue) work() {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case imageID := <-q.jobs:
+			q.process(imageID)
+		case <-q.closed:
+			return
+		}
+	}
+}
+
+// decide picks the action for a verdict. Unverified verdicts are published
+// but flagged, so a moderator still gets to see the image.
+func (q *ImageModerationQueue) decide(target domain.ImageTarget, inf nsfwFilter.Inference) nsfwFilter.Action {
+	if inf.Unverified {
+		return nsfwFilter.ActionFlag
+	}
+
+	policy, ok := q.policies[target]
+	if !ok {
+		policy = defaultNSFWPolicy
+	}
+
+	return policy.Decide(inf)
+}
+
+// Submit stores the images as pending and classifies them in the background.
+func (q *ImageModerationQueue) Submit(ownerID string, uploads ...*domain.ImageUpload) ([]string, error) {
+	imageIDs := make([]string, len(uploads))
+	for i, upload := range uploads {
+		imageID, err := q.pendingRepo.AddPendingImage(ownerID, upload, domain.StatusPending, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		imageIDs[i] = imageID
+		q.enqueue(imageID)
+	}
+
+	return imageIDs, nil
+}
+
+// Track puts an already classified image into the review queue: hidden images
+// wait there to be published, flagged ones are published and may be taken down.
+func (q *ImageModerationQueue) Track(ownerID string, upload *domain.ImageUpload, action nsfwFilter.Action, probability float64) (string, error) {
+	status := domain.StatusReview
+	if action == nsfwFilter.ActionFlag {
+		status = domain.StatusFlagged
+	}
+
+	return q.pendingRepo.AddPendingImage(ownerID, upload, status, probability)
+}
+
+func (q *ImageModerationQueue) process(imageID string) {
+	image, err := q.pendingRepo.GetPendingImage(imageID)
+	if err != nil {
+		q.onError(fmt.Errorf("moderation of image %s: %w", imageID, err))
+		return
+	}
+
+	if image.Status != domain.StatusPending {
+		return
+	}
+
+	results := q.imageModerator.RunInParallel(base64.StdEncoding.EncodeToString(image.Image))
+	if results[0].ProcessingErr != nil {
+		q.retry(imageID, results[0].ProcessingErr)
+		return
+	}
+
+	q.attemptsMu.Lock()
+	delete(q.attempts, imageID)
+	q.attemptsMu.Unlock()
+
+	probability := nsfwFilter.NSFWProbability(results[0].Inf)
+	setVerdict := func(status domain.ModerationStatus) error {
+		return q.pendingRepo.SetImageVerdict(imageID, status, probability)
+	}
+
+	switch q.decide(image.Target, results[0].Inf) {
+	case nsfwFilter.ActionAllow:
+		err = q.publish(image, domain.StatusApproved, setVerdict)
+	case nsfwFilter.ActionFlag:
+		err = q.publish(image, domain.StatusFlagged, setVerdict)
+	case nsfwFilter.ActionHide:
+		err = setVerdict(domain.StatusReview)
+	default:
+		err = setVerdict(domain.StatusRejected)
+		if err == nil {
+			q.notify(image, domain.NotificationImageRejected, "your "+imageTargetNames[image.Target]+" was rejected by moderation")
+		}
+	}
+
+	if err != nil {
+		q.onError(fmt.Errorf("moderation of image %s: %w", imageID, err))
+	}
+}
+
+// retry classifies the image again after moderationRetryDelay. After
+// maxModerationAttempts failures the image goes to the review queue instead.
+func (q *ImageModerationQueue) retry(imageID string, classifyErr error) {
+	q.attemptsMu.Lock()
+	q.attempts[imageID]++
+	attempts := q.attempts[imageID]
+	if attempts >= maxModerationAttempts {
+		delete(q.attempts, imageID)
+	}
+	q.attemptsMu.Unlock()
+
+	if attempts < maxModerationAttempts {
+		time.AfterFunc(moderationRetryDelay, func() { q.enqueue(imageID) })
+		return
+	}
+
+	err := q.pendingRepo.SetImageVerdict(imageID, domain.StatusReview, 0)
+	q.onError(fmt.Errorf("classification of image %s failed %d times, left for review: %w", imageID, attempts, errors.Join(classifyErr, err)))
+}
+
+// publish applies the image on its target and records status with setStatus.
+// An image whose target is gone or full is rejected instead.
+func (q *ImageModerationQueue) publish(image *domain.DBPendingImage, status domain.ModerationStatus, setStatus func(domain.ModerationStatus) error) error {
+	err := q.pendingRepo.ApplyImage(image)
+	if err != nil {
+		statusErr := setStatus(domain.StatusRejected)
+		if statusErr != nil {
+			return statusErr
+		}
+
+		q.notify(image, domain.NotificationImageRejected, "your "+imageTargetNames[image.Target]+" could not be published: "+err.Error())
+		return nil
+	}
+
+	err = setStatus(status)
+	if err != nil {
+		return err
+	}
+
+	if hash, hashErr := imageHash.DHash(image.Image); hashErr == nil {
+		if err = q.blocklist.Register([]uint64{hash}); err != nil {
+			q.onError(fmt.Errorf("hash of image %s: %w", image.ImageID.Hex(), err))
+		}
+	}
+
+	q.notify(image, domain.NotificationImageApproved, "your "+imageTargetNames[image.Target]+" has been approved and published")
+	return nil
+}
+
+// notify only reports failures, as the verdict is already recorded by then.
+func (q *ImageModerationQueue) notify(image *domain.DBPendingImage, kind domain.NotificationKind, message string) {
+	err := q.notificationRepo.AddNotification(image.OwnerID.Hex(), kind, message, image.ImageID.Hex())
+	if err != nil {
+		q.onError(fmt.Errorf("notification about image %s: %w", image.ImageID.Hex(), err))
+	}
+}
+
+func (q *ImageModerationQueue) GetReviewQueue() ([]*domain.ApiPendingImage, error) {
+	images, err := q.pendingRepo.GetImagesByStatus(domain.StatusReview, domain.StatusFlagged)
+	if err != nil {
+		return nil, err
+	}
+
+	apiImages := make([]*domain.ApiPendingImage, len(images))
+	for i, image := range images {
+		apiImages[i] = image.ToApi()
+	}
+
+	return apiImages, nil
+}
+
+func (q *ImageModerationQueue) GetUserPendingImages(userID string) ([]*domain.ApiPendingImage, error) {
+	images, err := q.pendingRepo.GetUserPendingImages(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiImages := make([]*domain.ApiPendingImage, len(images))
+	for i, image := range images {
+		apiImages[i] = image.ToApi()
+	}
+
+	return apiImages, nil
+}
+
+func (q *ImageModerationQueue) Approve(imageID, reviewerID string) error {
+	image, err := q.pendingRepo.GetPendingImage(imageID)
+	if err != nil {
+		return err
+	}
+
+	setReview := func(status domain.ModerationStatus) error {
+		return q.pendingRepo.ReviewImage(imageID, reviewerID, status)
+	}
+
+	switch image.Status {
+	case domain.StatusReview:
+		return q.publish(image, domain.StatusApproved, setReview)
+	case domain.StatusFlagged:
+		return setReview(domain.StatusApproved)
+	default:
+		return mongoTLC.NOT_UNDER_REVIEW
+	}
+}
+
+func (q *ImageModerationQueue) Reject(imageID, reviewerID string) error {
+	image, err := q.pendingRepo.GetPendingImage(imageID)
+	if err != nil {
+		return err
+	}
+
+	if image.Status == domain.StatusFlagged {
+		err = q.pendingRepo.RetractImage(image)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = q.pendingRepo.ReviewImage(imageID, reviewerID, domain.StatusRejected)
+	if err != nil {
+		return err
+	}
+
+	q.notify(image, domain.NotificationImageRejected, "your "+imageTargetNames[image.Target]+" was rejected by a moderator")
+	return nil
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"strings"
+
+	"mainService/configs"
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+	"mainService/pkg/imageHash"
+	"mainService/pkg/nsfwFilter"
+	"mainService/pkg/swearWordsDetector"
+)
+
+type IModerationUsecase interface {
+	BlockImage(moderatorID string, req *domain.BlockImageRequest) (*domain.BlockImageResponse, error)
+	GetImageHashStats(moderatorID string) (*domain.ImageHashStats, error)
+	GetNSFWCacheStats(moderatorID string) (*domain.NSFWCacheStats, error)
+	GetReviewQueue(moderatorID string) ([]*domain.ApiPendingImage, error)
+	ApproveImage(moderatorID, imageID string) error
+	RejectImage(moderatorID, imageID string) error
+	GetUserPendingImages(userID string) ([]*domain.ApiPendingImage, error)
+	GetSwearWords(moderatorID string) ([]*domain.ApiSwearWord, error)
+	AddSwearWord(moderatorID string, word *domain.ApiSwearWord) error
+	DeleteSwearWord(moderatorID, word string, allowed bool) error
+}
+
+type ModerationUsecase struct {
+	imageHashRepo   mongoTLC.IImageHashRepository
+	swearWordRepo   mongoTLC.ISwearWordRepository
+	moderationQueue *ImageModerationQueue
+	detector        *swearWordsDetector.Detector
+}
+
+func NewModerationUsecase(
+	imageHashRepository mongoTLC.IImageHashRepository,
+	swearWordRepository mongoTLC.ISwearWordRepository,
+	moderationQueue *ImageModerationQueue,
+	detector *swearWordsDetector.Detector,
+) IModerationUsecase {
+	return &ModerationUsecase{
+		imageHashRepo:   imageHashRepository,
+		swearWordRepo:   swearWordRepository,
+		moderationQueue: moderationQueue,
+		detector:        detector,
+	}
+}
+
+func isModerator(userID string) bool {
+	_, ok := configs.ModeratorIDs[userID]
+	return ok
+}
+
+func (ucase *ModerationUsecase) BlockImage(moderatorID string, req *domain.BlockImageRequest) (*domain.BlockImageResponse, error) {
+	if !isModerator(moderatorID) {
+		return nil, NOT_A_MODERATOR
+	}
+
+	var hash uint64
+	var err error
+	if req.Image != "" {
+		hash, err = hashBase64Image(req.Image)
+	} else if req.Hash != "" {
+		hash, err = imageHash.FromHex(req.Hash)
+	} else {
+		return nil, EMPTY_IMAGE
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = ucase.imageHashRepo.BlockHash(hash, moderatorID, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.BlockImageResponse{Hash: imageHash.ToHex(hash)}, nil
+}
+
+func (ucase *ModerationUsecase) GetImageHashStats(moderatorID string) (*domain.ImageHashStats, error) {
+	if !isModerator(moderatorID) {
+		return nil, NOT_A_MODERATOR
+	}
+
+	return ucase.imageHashRepo.GetHashStats()
+}
+
+func (ucase *ModerationUsecase) GetNSFWCacheStats(moderatorID string) (*domain.NSFWCacheStats, error) {
+	if !isModerator(moderatorID) {
+		return nil, NOT_A_MODERATOR
+	}
+
+	hits, misses := nsfwFilter.CacheStats()
+
+	stats := &domain.NSFWCacheStats{
+		Hits:   hits,
+		Misses: misses,
+	}
+
+	if hits+misses != 0 {
+		stats.HitRate = float64(hits) / float64(hits+misses)
+	}
+
+	return stats, nil
+}
+
+func (ucase *ModerationUsecase) GetReviewQueue(moderatorID string) ([]*domain.ApiPendingImage, error) {
+	if !isModerator(moderatorID) {
+		return nil, NOT_A_MODERATOR
+	}
+
+	return ucase.moderationQueue.GetReviewQueue()
+}
+
+func (ucase *ModerationUsecase) ApproveImage(moderatorID, imageID string) error {
+	if !isModerator(moderatorID) {
+		return NOT_A_MODERATOR
+	}
+
+	return ucase.moderationQueue.Approve(imageID, moderatorID)
+}
+
+func (ucase *ModerationUsecase) RejectImage(moderatorID, imageID string) error {
+	if !isModerator(moderatorID) {
+		return NOT_A_MODERATOR
+	}
+
+	return ucase.moderationQueue.Reject(imageID, moderatorID)
+}
+
+func (ucase *ModerationUsecase) GetUserPendingImages(userID string) ([]*domain.ApiPendingImage, error) {
+	return ucase.moderationQueue.GetUserPendingImages(userID)
+}
+
+// GetSwearWords lists the words added at runtime. Words from the files are
+// not included.
+func (ucase *ModerationUsecase) GetSwearWords(moderatorID string) ([]*domain.ApiSwearWord, error) {
+	if !isModerator(moderatorID) {
+		return nil, NOT_A_MODERATOR
+	}
+
+	words, err := ucase.swearWordRepo.GetSwearWords()
+	if err != nil {
+		return nil, err
+	}
+
+	apiWords := make([]*domain.ApiSwearWord, len(words))
+	for i, word := range words {
+		apiWords[i] = word.ToApi()
+	}
+
+	return apiWords, nil
+}
+
+func (ucase *ModerationUsecase) AddSwearWord(moderatorID string, word *domain.ApiSwearWord) error {
+	if !isModerator(moderatorID) {
+		return NOT_A_MODERATOR
+	}
+
+	word.Word = strings.TrimSpace(word.Word)
+	if word.Word == "" {
+		return swearWordsDetector.EMPTY_WORD_ERR
+	}
+
+	severity, err := swearWordsDetector.ParseSeverity(word.Severity)
+	if err != nil {
+		return err
+	}
+	word.Severity = string(severity)
+
+	err = ucase.swearWordRepo.AddSwearWord(moderatorID, word)
+	if err != nil {
+		return err
+	}
+
+	return ucase.detector.Reload()
+}
+
+func (ucase *ModerationUsecase) DeleteSwearWord(moderatorID, word string, allowed bool) error {
+	if !isModerator(moderatorID) {
+		return NOT_A_MODERATOR
+	}
+
+	err := ucase.swearWordRepo.DeleteSwearWord(strings.TrimSpace(word), allowed)
+	if err != nil {
+		return err
+	}
+
+	return ucase.detector.Reload()
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+)
+
+type INotificationUsecase interface {
+	GetUserNotifications(userID string) ([]*domain.ApiNotification, error)
+	MarkNotificationsRead(userID string) error
+}
+
+type NotificationUsecase struct {
+	notificationRepo mongoTLC.INotificationRepository
+}
+
+func NewNotificationUsecase(
+	notificationRepository mongoTLC.INotificationRepository,
+) INotificationUsecase {
+	return &NotificationUsecase{
+		notificationRepo: notificationRepository,
+	}
+}
+
+func (ucase *NotificationUsecase) GetUserNotifications(userID string) ([]*domain.ApiNotification, error) {
+	return ucase.notificationRepo.GetUserNotifications(userID)
+}
+
+func (ucase *NotificationUsecase) MarkNotificationsRead(userID string) error {
+	return ucase.notificationRepo.MarkNotificationsRead(userID)
+}
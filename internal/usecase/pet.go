@@ -2,25 +2,39 @@ package usecase
 
 import (
 	"encoding/base64"
+	"errors"
 	"mainService/internal/domain"
 	"mainService/internal/repository/mongoTLC"
+	"mainService/pkg/animalTaxonomy"
+	"mainService/pkg/petAdviser"
+	"mainService/pkg/resilience"
 )
 
+const adviceFallback = "The adviser is temporarily unavailable, please try again later. Meanwhile, keep your pet fed, watered and warm, and see a vet if anything worries you."
+
 type IPetUsecase interface {
 	GetPetInfo(petID string) (*domain.ApiPetInfo, error)
 	GetPetAvatar(petID string) (string, error)
 	GetTopAnimals(top int64) ([]string, error)
+	LookupAnimals(query, lang string, limit int64) ([]*domain.ApiAnimal, error)
+	GetPetCareAdvice(animal, prompt string) (*domain.PetAdviceResponse, error)
 }
 
 type PetUsecase struct {
-	petRepo mongoTLC.IPetRepository
+	petRepo      mongoTLC.IPetRepository
+	adviser      *petAdviser.Client
+	adviserGuard *resilience.Guard
 }
 
 func NewPetUsecase(
 	petRepository mongoTLC.IPetRepository,
+	adviser *petAdviser.Client,
+	adviserGuard *resilience.Guard,
 ) IPetUsecase {
 	return &PetUsecase{
-		petRepo: petRepository,
+		petRepo:      petRepository,
+		adviser:      adviser,
+		adviserGuard: adviserGuard,
 	}
 }
 
@@ -59,3 +73,57 @@ func (ucase *PetUsecase) GetTopAnimals(top int64) ([]string, error) {
 
 	return ucase.petRepo.GetTopAnimals(top)
 }
+
+// LookupAnimals finds the taxonomy entries with a name starting with the
+// query, named in lang.
+func (ucase *PetUsecase) LookupAnimals(query, lang string, limit int64) ([]*domain.ApiAnimal, error) {
+	if lang == "" {
+		lang = animalTaxonomy.DefaultLang
+	} else if lang != animalTaxonomy.LangEn && lang != animalTaxonomy.LangRu {
+		return nil, BAD_ANIMAL_LANGUAGE
+	}
+
+	if limit < 0 {
+		return nil, POSITIVE_NUMBER_REQUIRED
+	}
+
+	found := animalTaxonomy.Lookup(query, lang, int(limit))
+
+	animals := make([]*domain.ApiAnimal, len(found))
+	for i, animal := range found {
+		animals[i] = &domain.ApiAnimal{
+			AnimalID: animal.ID,
+			Species:  animal.Species,
+			Name:     animal.Name(lang),
+			Synonyms: animal.Names[lang],
+		}
+	}
+
+	return animals, nil
+}
+
+func (ucase *PetUsecase) GetPetCareAdvice(animal, prompt string) (*domain.PetAdviceResponse, error) {
+	var advice *petAdviser.Advice
+	err := ucase.adviserGuard.Do(func() error {
+		var adviceErr error
+		advice, adviceErr = ucase.adviser.GetAdvice(animal, prompt)
+		return adviceErr
+	})
+
+	if errors.Is(err, resilience.UNAVAILABLE_ERR) && ucase.adviserGuard.FailsOpen() {
+		return &domain.PetAdviceResponse{
+			Advice:   adviceFallback,
+			Animal:   animal,
+			Prompt:   prompt,
+			Degraded: true,
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &domain.PetAdviceResponse{
+		Advice: advice.Advice,
+		Animal: advice.Animal,
+		Prompt: advice.Prompt,
+	}, nil
+}
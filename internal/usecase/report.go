@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"mainService/configs"
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+)
+
+type IReportUsecase interface {
+	ReportContent(userID string, report *domain.ApiReport) error
+	GetCases(moderatorID string, status domain.CaseStatus) ([]*domain.ApiModerationCase, error)
+	GetCase(moderatorID, caseID string) (*domain.ApiModerationCase, error)
+	ApplyCaseAction(moderatorID string, req *domain.ApiCaseActionRequest) error
+}
+
+type ReportUsecase struct {
+	caseRepo         mongoTLC.IModerationCaseRepository
+	userRepo         mongoTLC.IUserRepository
+	notificationRepo mongoTLC.INotificationRepository
+}
+
+func NewReportUsecase(
+	caseRepository mongoTLC.IModerationCaseRepository,
+	userRepository mongoTLC.IUserRepository,
+	notificationRepository mongoTLC.INotificationRepository,
+) IReportUsecase {
+	return &ReportUsecase{
+		caseRepo:         caseRepository,
+		userRepo:         userRepository,
+		notificationRepo: notificationRepository,
+	}
+}
+
+// checkNotSuspended refuses users whose suspension has not run out yet.
+func checkNotSuspended(userRepo mongoTLC.IUserRepository, userID string) error {
+	until, err := userRepo.GetSuspendedUntil(userID)
+	if err != nil {
+		return err
+	}
+
+	if time.Now().Before(until) {
+		return fmt.Errorf("%w until %s", USER_SUSPENDED, until.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func (ucase *ReportUsecase) ReportContent(userID string, report *domain.ApiReport) error {
+	if !domain.IsReportTarget(report.Target) {
+		return BAD_REPORT_TARGET
+	}
+
+	if !domain.IsReportReason(report.Reason) {
+		return BAD_REPORT_REASON
+	}
+
+	ownerID, err := ucase.caseRepo.GetTargetOwner(report.Target, report.TargetID)
+	if err != nil {
+		return err
+	}
+
+	modCase, err := ucase.caseRepo.AddReport(userID, ownerID, report)
+	if err != nil {
+		return err
+	}
+
+	if modCase.Hidden || modCase.ReportCount < configs.ReportHideThreshold {
+		return nil
+	}
+
+	return ucase.hideAutomatically(modCase)
+}
+
+// hideAutomatically hides the content of a case that got too many reports
+// until a moderator decides on it.
+func (ucase *ReportUsecase) hideAutomatically(modCase *domain.ApiModerationCase) error {
+	err := ucase.caseRepo.SetTargetHidden(modCase.Target, modCase.TargetID, true)
+	if err != nil {
+		return err
+	}
+
+	entry := &domain.ApiCaseActionEntry{
+		Action:    domain.ActionHideContent,
+		Comment:   fmt.Sprintf("%d reports", modCase.ReportCount),
+		Automatic: true,
+		CreatedAt: time.Now(),
+	}
+
+	err = ucase.caseRepo.AddCaseAction(modCase.CaseID, entry, domain.CaseOpen, true)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("%s %s has been hidden after %d reports", modCase.Target, modCase.TargetID, modCase.ReportCount)
+	for moderatorID := range configs.ModeratorIDs {
+		err = ucase.notificationRepo.AddNotification(moderatorID, domain.NotificationContentHidden, message, modCase.CaseID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ucase.notificationRepo.AddNotification(
+		modCase.OwnerID,
+		domain.NotificationContentHidden,
+		fmt.Sprintf("your %s has been hidden until a moderator reviews the reports on it", modCase.Target),
+		modCase.TargetID,
+	)
+}
+
+func (ucase *ReportUsecase) GetCases(moderatorID string, status domain.CaseStatus) ([]*domain.ApiModerationCase, error) {
+	if !isModerator(moderatorID) {
+		return nil, NOT_A_MODERATOR
+	}
+
+	return ucase.caseRepo.GetCases(status)
+}
+
+func (ucase *ReportUsecase) GetCase(moderatorID, caseID string) (*domain.ApiModerationCase, error) {
+	if !isModerator(moderatorID) {
+		return nil, NOT_A_MODERATOR
+	}
+
+	return ucase.caseRepo.GetCase(caseID)
+}
+
+// ApplyCaseAction carries out the decision of a moderator and records it in
+// the audit trail of the case. Closed cases only take unhide, so that a
+// wrong hide can be undone.
+func (ucase *ReportUsecase) ApplyCaseAction(moderatorID string, req *domain.ApiCaseActionRequest) error {
+	if !isModerator(moderatorID) {
+		return NOT_A_MODERATOR
+	}
+
+	if !domain.IsCaseAction(req.Action) {
+		return BAD_CASE_ACTION
+	}
+
+	modCase, err := ucase.caseRepo.GetCase(req.CaseID)
+	if err != nil {
+		return err
+	}
+
+	if modCase.Status != domain.CaseOpen && req.Action != domain.ActionUnhideContent {
+		return CASE_CLOSED
+	}
+
+	entry := &domain.ApiCaseActionEntry{
+		ModeratorID: moderatorID,
+		Action:      req.Action,
+		Comment:     req.Comment,
+		CreatedAt:   time.Now(),
+	}
+	status, hidden := domain.CaseResolved, modCase.Hidden
+
+	switch req.Action {
+	case domain.ActionHideContent:
+		hidden = true
+	case domain.ActionUnhideContent:
+		hidden = false
+		if modCase.Status == domain.CaseOpen {
+			status = domain.CaseDismissed
+		} else {
+			status = modCase.Status
+		}
+	case domain.ActionDismissCase:
+		status, hidden = domain.CaseDismissed, false
+	case domain.ActionWarnUser:
+		message := fmt.Sprintf("your %s has been reported and reviewed by a moderator", modCase.Target)
+		if req.Comment != "" {
+			message += ": " + req.Comment
+		}
+
+		err = ucase.notificationRepo.AddNotification(modCase.OwnerID, domain.NotificationWarning, message, modCase.TargetID)
+		if err != nil {
+			return err
+		}
+	case domain.ActionSuspendUser:
+		days := req.SuspendDays
+		if days <= 0 {
+			days = configs.DefaultSuspendDays
+		}
+
+		until := entry.CreatedAt.AddDate(0, 0, days)
+		err = ucase.userRepo.SuspendUser(modCase.OwnerID, until)
+		if err != nil {
+			return err
+		}
+		entry.SuspendedUntil = &until
+
+		message := "your account is suspended until " + until.Format(time.RFC3339)
+		if req.Comment != "" {
+			message += ": " + req.Comment
+		}
+
+		err = ucase.notificationRepo.AddNotification(modCase.OwnerID, domain.NotificationSuspended, message, modCase.TargetID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the content may have been deleted since it was reported
+	if hidden != modCase.Hidden {
+		err = ucase.caseRepo.SetTargetHidden(modCase.Target, modCase.TargetID, hidden)
+		if err != nil && !errors.Is(err, mongoTLC.NOT_FOUND) {
+			return err
+		}
+	}
+
+	return ucase.caseRepo.AddCaseAction(modCase.CaseID, entry, status, hidden)
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"mainService/configs"
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+	"mainService/pkg/mailSender"
+)
+
+const savedSearchConfirmSubject = "Confirm your e-mail for saved search alerts"
+
+type ISavedSearchUsecase interface {
+	SaveSearch(userID string, search *domain.ApiSavedSearch) (*domain.ApiSavedSearch, error)
+	GetSavedSearches(userID string) ([]*domain.ApiSavedSearch, error)
+	DeleteSavedSearch(userID, searchID string) error
+	ConfirmEmail(searchID, token string) error
+}
+
+type SavedSearchUsecase struct {
+	searchRepo mongoTLC.ISavedSearchRepository
+	userRepo   mongoTLC.IUserRepository
+	// sender is nil when e-mails are off.
+	sender mailSender.Sender
+}
+
+func NewSavedSearchUsecase(
+	savedSearchRepository mongoTLC.ISavedSearchRepository,
+	userRepository mongoTLC.IUserRepository,
+	sender mailSender.Sender,
+) ISavedSearchUsecase {
+	return &SavedSearchUsecase{
+		searchRepo: savedSearchRepository,
+		userRepo:   userRepository,
+		sender:     sender,
+	}
+}
+
+func (ucase *SavedSearchUsecase) SaveSearch(userID string, search *domain.ApiSavedSearch) (*domain.ApiSavedSearch, error) {
+	if err := checkNotSuspended(ucase.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	search.Query = strings.TrimSpace(search.Query)
+	filter := search.Filter
+	if filter == nil {
+		filter = &domain.ServiceFilter{}
+	}
+
+	if search.Query == "" && filter.MinPrice == 0 && filter.MaxPrice == 0 && len(filter.Animals) == 0 {
+		return nil, EMPTY_SAVED_SEARCH
+	}
+
+	if (filter.MinPrice > filter.MaxPrice && (filter.MaxPrice != 0)) || (filter.MinPrice < 0) || (filter.MaxPrice < 0) {
+		return nil, INVALID_PRICE_RANGE
+	}
+
+	emailToken := ""
+	if search.Email != "" {
+		if ucase.sender == nil {
+			return nil, EMAILS_DISABLED
+		}
+
+		address, err := mail.ParseAddress(search.Email)
+		if err != nil {
+			return nil, BAD_EMAIL
+		}
+
+		search.Email = address.Address
+		emailToken = uuid.NewString()
+	}
+
+	count, err := ucase.searchRepo.CountUserSavedSearches(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if count >= int64(configs.MaxSavedSearches) {
+		return nil, TOO_MANY_SAVED_SEARCHES
+	}
+
+	saved, err := ucase.searchRepo.AddSavedSearch(userID, search, emailToken)
+	if err != nil || emailToken == "" {
+		return saved, err
+	}
+
+	// digests only go to addresses proven to belong to the user, so the
+	// search is not kept when the confirmation can't be sent
+	err = ucase.sender.Send(saved.Email, savedSearchConfirmSubject, confirmEmailBody(saved.SavedSearchID, emailToken))
+	if err != nil {
+		return nil, errors.Join(err, ucase.searchRepo.DeleteSavedSearch(userID, saved.SavedSearchID))
+	}
+
+	return saved, nil
+}
+
+func confirmEmailBody(searchID, token string) string {
+	return "Someone asked to get the new services matching a saved search at this address.\n" +
+		"If it was you, confirm it with saved search " + searchID + " and code " + token + ".\n" +
+		"Otherwise just ignore this e-mail.\n"
+}
+
+func (ucase *SavedSearchUsecase) GetSavedSearches(userID string) ([]*domain.ApiSavedSearch, error) {
+	return ucase.searchRepo.GetUserSavedSearches(userID)
+}
+
+func (ucase *SavedSearchUsecase) DeleteSavedSearch(userID, searchID string) error {
+	return ucase.searchRepo.DeleteSavedSearch(userID, searchID)
+}
+
+func (ucase *SavedSearchUsecase) ConfirmEmail(searchID, token string) error {
+	return ucase.searchRepo.ConfirmSavedSearchEmail(searchID, token)
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+	"mainService/pkg/highlighter"
+	"mainService/pkg/mailSender"
+)
+
+const searchDigestSubject = "New services for your saved searches"
+
+// searchAlertsEnqueueTimeout is how long adding a service waits for room in
+// the alerts queue.
+const searchAlertsEnqueueTimeout = time.Second
+
+// SearchAlerts checks new services against the saved searches in the
+// background. Every match is a notification right away, while e-mails are
+// collected and sent as one digest per address every digest interval.
+type SearchAlerts struct {
+	searchRepo       mongoTLC.ISavedSearchRepository
+	serviceRepo      mongoTLC.IServiceRepository
+	notificationRepo mongoTLC.INotificationRepository
+	// sender is nil when e-mails are off.
+	sender         mailSender.Sender
+	digestInterval time.Duration
+	// onError reports the errors of the background work.
+	onError func(err error)
+
+	jobs   chan string
+	closed chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewSearchAlerts(
+	savedSearchRepository mongoTLC.ISavedSearchRepository,
+	serviceRepository mongoTLC.IServiceRepository,
+	notificationRepository mongoTLC.INotificationRepository,
+	sender mailSender.Sender,
+	digestInterval time.Duration,
+	onError func(err error),
+) *SearchAlerts {
+	return &SearchAlerts{
+		searchRepo:       savedSearchRepository,
+		serviceRepo:      serviceRepository,
+		notificationRepo: notificationRepository,
+		sender:           sender,
+		digestInterval:   digestInterval,
+		onError:          onError,
+		jobs:             make(chan string, 256),
+		closed:           make(chan struct{}),
+	}
+}
+
+func (a *SearchAlerts) Start() {
+	a.wg.Add(1)
+	go a.work()
+
+	if a.sender != nil {
+		a.wg.Add(1)
+		go a.sendDigests()
+	}
+}
+
+// Close waits for the services already queued to be matched.
+func (a *SearchAlerts) Close() {
+	close(a.closed)
+	a.wg.Wait()
+}
+
+// ServiceAdded queues a new service to be checked against the saved searches.
+// When the queue stays full for searchAlertsEnqueueTimeout the service is
+// dropped and reported.
+func (a *SearchAlerts) ServiceAdded(serviceID string) {
+	timer := time.NewTimer(searchAlertsEnqueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case a.jobs <- serviceID:
+	case <-a.closed:
+	case <-timer.C:
+		a.onError(fmt.Errorf("%w: service %s dropped", SEARCH_ALERTS_QUEUE_FULL, serviceID))
+	}
+}
+
+func (a *SearchAlerts) work() {
+	defer a.wg.Done()
+
+	for {
+		select {
+		case serviceID := <-a.jobs:
+			a.matchAndReport(serviceID)
+		case <-a.closed:
+			// the services queued before closing are still alerted about
+			for {
+				select {
+				case serviceID := <-a.jobs:
+					a.matchAndReport(serviceID)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (a *SearchAlerts) matchAndReport(serviceID string) {
+	if err := a.match(serviceID); err != nil {
+		a.onError(fmt.Errorf("saved search alerts for service %s: %w", serviceID, err))
+	}
+}
+
+func (a *SearchAlerts) match(serviceID string) error {
+	searches, err := a.searchRepo.GetSavedSearchesForService(serviceID)
+	if err != nil || len(searches) == 0 {
+		return err
+	}
+
+	service, err := a.serviceRepo.GetServiceByID(serviceID)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, search := range searches {
+		if search.Query != "" && !highlighter.New(search.Query).MatchesAll(service.Title, service.Description) {
+			continue
+		}
+
+		message := "new service \"" + service.Title + "\" matches your saved search"
+		if search.Query != "" {
+			message += " \"" + search.Query + "\""
+		}
+		// a failed search doesn't keep the others from being alerted
+		err = a.notificationRepo.AddNotification(search.UserID, domain.NotificationSearchMatch, message, serviceID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notification for saved search %s: %w", search.SavedSearchID, err))
+		}
+
+		if a.sender != nil && search.Email != "" && search.EmailConfirmed {
+			err = a.searchRepo.AddSearchAlert(search, service)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("e-mail alert for saved search %s: %w", search.SavedSearchID, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (a *SearchAlerts) sendDigests() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.digestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := a.sendDigestsOnce(); err != nil {
+				a.onError(fmt.Errorf("saved search digests: %w", err))
+			}
+		case <-a.closed:
+			return
+		}
+	}
+}
+
+// sendDigestsOnce sends the alerts collected so far. The alerts of an address
+// the digest failed to reach are kept for the next round.
+func (a *SearchAlerts) sendDigestsOnce() error {
+	alerts, err := a.searchRepo.GetUnsentSearchAlerts()
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(alerts); {
+		end := start + 1
+		for end < len(alerts) && alerts[end].Email == alerts[start].Email {
+			end++
+		}
+
+		batch := alerts[start:end]
+		start = end
+
+		err = a.sender.Send(batch[0].Email, searchDigestSubject, digestBody(batch))
+		if err != nil {
+			a.onError(fmt.Errorf("saved search digest to %s: %w", batch[0].Email, err))
+			continue
+		}
+
+		alertIDs := make([]bson.ObjectID, len(batch))
+		for i, alert := range batch {
+			alertIDs[i] = alert.AlertID
+		}
+
+		err = a.searchRepo.DeleteSearchAlerts(alertIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// digestBody lists the services by the saved search they matched.
+func digestBody(alerts []*domain.DBSearchAlert) string {
+	var b strings.Builder
+	b.WriteString("New services matching your saved searches:\n")
+
+	listed := map[bson.ObjectID]bool{}
+	for i, alert := range alerts {
+		if listed[alert.SavedSearchID] {
+			continue
+		}
+		listed[alert.SavedSearchID] = true
+
+		query := alert.Query
+		if query == "" {
+			query = "any service"
+		}
+		b.WriteString("\n" + query + ":\n")
+
+		for _, match := range alerts[i:] {
+			if match.SavedSearchID == alert.SavedSearchID {
+				fmt.Fprintf(&b, "  - %s, %d\n", match.Title, match.Price)
+			}
+		}
+	}
+
+	return b.String()
+}
@@ -2,37 +2,69 @@ package usecase
 
 import (
 	"encoding/base64"
+	"errors"
 	"mainService/internal/domain"
 	"mainService/internal/repository/mongoTLC"
-	"mainService/pkg/serverErrors"
-	"mainService/pkg/swearWordsDetector"
+	"mainService/pkg/highlighter"
+	"mainService/pkg/nsfwFilter"
+	"mainService/pkg/textLanguage"
+	"slices"
 	"strings"
 )
 
+// SNIPPET_LENGTH is the length in runes of the description snippets of
+// search results.
+const SNIPPET_LENGTH = 160
+
+// FEED_TOP_ANIMALS is the number of the most common animals the feed of a
+// user without pets is built around.
+const FEED_TOP_ANIMALS = 5
+
 type IServiceUsecase interface {
 	AddService(userID string, service *domain.ApiService) (*domain.ApiService, error)
 	GetServiceByID(serviceID string) (*domain.ApiService, error)
 	GetUserServices(userID string) ([]*domain.ApiService, error)
-	GetAllServices() ([]*domain.ApiService, error)
+	GetAllServices(viewerID string) ([]*domain.ApiService, error)
 	DeleteService(userID, serviceID string) error
-	SearchServices(queryString string, filters *domain.ServiceFilter) ([]*domain.ApiService, error)
+	SearchServices(queryString string, filters *domain.ServiceFilter, viewerID string) (*domain.ServiceSearchResult, error)
+	AddServicePhoto(userID, serviceID string, photo *domain.ApiServicePhoto) (*domain.ApiServicePhoto, error)
+	DeleteServicePhoto(userID, serviceID, photoID string) error
+	SetServiceCover(userID, serviceID, photoID string) error
+	ReorderServicePhotos(userID, serviceID string, order *domain.ServicePhotoOrder) error
+	Autocomplete(input string, limit int64) (*domain.Suggestions, error)
+	GetFeed(userID string, page, pageSize int64) (*domain.Feed, error)
+	GetSimilarServices(serviceID string, limit int64, viewerID string) ([]*domain.SimilarService, error)
 }
 
 type ServiceUsecase struct {
-	serviceRepo mongoTLC.IServiceRepository
-	userRepo    mongoTLC.IUserRepository
-	petRepo     mongoTLC.IPetRepository
+	serviceRepo  mongoTLC.IServiceRepository
+	userRepo     mongoTLC.IUserRepository
+	petRepo      mongoTLC.IPetRepository
+	favoriteRepo mongoTLC.IFavoriteRepository
+	images       *imageGate
+	texts        TextModerator
+	alerts       *SearchAlerts
 }
 
 func NewServiceUsecase(
 	serviceRepository mongoTLC.IServiceRepository,
 	userRepository mongoTLC.IUserRepository,
 	petRepository mongoTLC.IPetRepository,
+	favoriteRepository mongoTLC.IFavoriteRepository,
+	imageHashRepository mongoTLC.IImageHashRepository,
+	imageModerator nsfwFilter.ImageModerator,
+	moderationQueue *ImageModerationQueue,
+	textModerator TextModerator,
+	searchAlerts *SearchAlerts,
 ) IServiceUsecase {
 	return &ServiceUsecase{
-		serviceRepo: serviceRepository,
-		userRepo:    userRepository,
-		petRepo:     petRepository,
+		serviceRepo:  serviceRepository,
+		userRepo:     userRepository,
+		petRepo:      petRepository,
+		favoriteRepo: favoriteRepository,
+		images:       newImageGate(imageHashRepository, imageModerator, moderationQueue),
+		texts:        textModerator,
+		alerts:       searchAlerts,
 	}
 }
 
@@ -42,9 +74,16 @@ func (ucase *ServiceUsecase) AddService(userID string, service *domain.ApiServic
 		return nil, INVALID_ROLE
 	}
 
-	containsSwearWords := swearWordsDetector.DetectInMultipleInputs(service.Description, service.Title)
-	if containsSwearWords {
-		return nil, serverErrors.SWEAR_WORDS_ERROR
+	if err := checkNotSuspended(ucase.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	flaggedTexts, textErr := ucase.texts.Check(
+		TextInput{domain.FieldServiceDescription, &service.Description},
+		TextInput{domain.FieldServiceTitle, &service.Title},
+	)
+	if textErr != nil {
+		return nil, textErr
 	}
 
 	if service.Title == "" {
@@ -56,13 +95,18 @@ func (ucase *ServiceUsecase) AddService(userID string, service *domain.ApiServic
 		return nil, err
 	}
 
+	err = ucase.texts.Flag(serviceID, flaggedTexts)
+	if err != nil {
+		return nil, err
+	}
+
 	serviceIDStruct := &domain.ApiService{
 		ServiceID: serviceID,
 	}
 
 	if len(service.PetIDs) != 0 {
 		for _, petID := range service.PetIDs {
-			info, err := ucase.petRepo.GetPetInfo(petID)
+			info, err := ucase.petRepo.GetPetInfoIncludingHidden(petID)
 			if err != nil {
 				return nil, err
 			}
@@ -76,6 +120,8 @@ func (ucase *ServiceUsecase) AddService(userID string, service *domain.ApiServic
 		}
 	}
 
+	ucase.alerts.ServiceAdded(serviceID)
+
 	return serviceIDStruct, nil
 }
 
@@ -115,6 +161,8 @@ func (ucase *ServiceUsecase) GetUserServices(userID string) ([]*domain.ApiServic
 			serv.PetIDs = []string{}
 		}
 
+		serv.Photos = nil
+
 		avatar, err := ucase.userRepo.GetAvatarBytes(serv.UserID)
 		if err != nil {
 			return nil, err
@@ -126,17 +174,25 @@ func (ucase *ServiceUsecase) GetUserServices(userID string) ([]*domain.ApiServic
 	return services, nil
 }
 
-func (ucase *ServiceUsecase) GetAllServices() ([]*domain.ApiService, error) {
+// GetAllServices marks the favorites of the viewer, if one is given.
+func (ucase *ServiceUsecase) GetAllServices(viewerID string) ([]*domain.ApiService, error) {
 	services, err := ucase.serviceRepo.GetAllServices()
 	if err != nil {
 		return nil, err
 	}
 
+	err = ucase.markFavorites(viewerID, services)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, serv := range services {
 		if len(serv.PetIDs) == 0 {
 			serv.PetIDs = []string{}
 		}
 
+		serv.Photos = nil
+
 		avatar, err := ucase.userRepo.GetAvatarBytes(serv.UserID)
 		if err != nil {
 			return nil, err
@@ -149,21 +205,23 @@ func (ucase *ServiceUsecase) GetAllServices() ([]*domain.ApiService, error) {
 }
 
 func (ucase *ServiceUsecase) DeleteService(userID, serviceID string) error {
-	servInfo, err := ucase.serviceRepo.GetServiceByID(serviceID)
+	servInfo, err := ucase.serviceRepo.GetServiceByIDIncludingHidden(serviceID)
 	if err != nil {
 		return err
 	}
 
 	if len(servInfo.PetIDs) != 0 {
 		for _, petID := range servInfo.PetIDs {
-			petInfo, err := ucase.petRepo.GetPetInfo(petID)
+			petInfo, err := ucase.petRepo.GetPetInfoIncludingHidden(petID)
 			if err != nil {
 				return err
 			}
 
 			if petInfo.TypeOfAnimal != "" {
+				// the row may be gone already when the counts drifted, which
+				// must not keep the service from being deleted
 				err = ucase.petRepo.DecrementAnimal(petInfo.TypeOfAnimal, serviceID)
-				if err != nil {
+				if err != nil && !errors.Is(err, mongoTLC.NOT_FOUND) {
 					return err
 				}
 			}
@@ -175,24 +233,84 @@ func (ucase *ServiceUsecase) DeleteService(userID, serviceID string) error {
 		return err
 	}
 
+	err = ucase.favoriteRepo.DeleteTargetFavorites(domain.FavoriteService, serviceID)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (ucase *ServiceUsecase) SearchServices(queryString string, filters *domain.ServiceFilter) ([]*domain.ApiService, error) {
+func (ucase *ServiceUsecase) markFavorites(viewerID string, services []*domain.ApiService) error {
+	if viewerID == "" || len(services) == 0 {
+		return nil
+	}
+
+	serviceIDs := make([]string, len(services))
+	for i, serv := range services {
+		serviceIDs[i] = serv.ServiceID
+	}
+
+	favorites, err := ucase.favoriteRepo.GetFavoriteSet(viewerID, domain.FavoriteService, serviceIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, serv := range services {
+		_, serv.IsFavorite = favorites[serv.ServiceID]
+	}
+
+	return nil
+}
+
+func (ucase *ServiceUsecase) SearchServices(queryString string, filters *domain.ServiceFilter, viewerID string) (*domain.ServiceSearchResult, error) {
 	if (filters.MinPrice > filters.MaxPrice && (filters.MaxPrice != 0)) || (filters.MinPrice < 0) || (filters.MaxPrice < 0) {
 		return nil, INVALID_PRICE_RANGE
 	}
 
-	services, err := ucase.serviceRepo.SearchServices(strings.TrimSpace(queryString), filters)
+	if filters.Sort != "" && !domain.IsSearchSort(filters.Sort) {
+		return nil, INVALID_SEARCH_SORT
+	}
+
+	if filters.Page < 0 || filters.PageSize < 0 {
+		return nil, INVALID_SEARCH_PAGE
+	}
+
+	if filters.Language != "" && !textLanguage.IsLanguage(textLanguage.Language(filters.Language)) {
+		return nil, INVALID_SEARCH_LANGUAGE
+	}
+
+	queryString = strings.TrimSpace(queryString)
+	result, err := ucase.serviceRepo.SearchServices(queryString, filters)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, serv := range services {
+	err = ucase.markFavorites(viewerID, result.Services)
+	if err != nil {
+		return nil, err
+	}
+
+	var h *highlighter.Highlighter
+	if queryString != "" {
+		h = highlighter.New(queryString)
+	}
+
+	for _, serv := range result.Services {
 		if len(serv.PetIDs) == 0 {
 			serv.PetIDs = []string{}
 		}
 
+		serv.Photos = nil
+
+		if h != nil {
+			description, _ := h.Snippet(serv.Description, SNIPPET_LENGTH)
+			serv.Highlight = &domain.ServiceHighlight{
+				Title:       h.Highlight(serv.Title),
+				Description: description,
+			}
+		}
+
 		avatar, err := ucase.userRepo.GetAvatarBytes(serv.UserID)
 		if err != nil {
 			return nil, err
@@ -201,5 +319,211 @@ func (ucase *ServiceUsecase) SearchServices(queryString string, filters *domain.
 		serv.UserImage = base64.StdEncoding.EncodeToString(avatar)
 	}
 
-	return services, err
+	return result, err
+}
+
+func (ucase *ServiceUsecase) AddServicePhoto(userID, serviceID string, photo *domain.ApiServicePhoto) (*domain.ApiServicePhoto, error) {
+	if photo.Image == "" {
+		return nil, EMPTY_IMAGE
+	}
+
+	if err := checkNotSuspended(ucase.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	// the photo is only classified once it may be added to the service
+	service, err := ucase.serviceRepo.GetServiceByIDIncludingHidden(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if service.UserID != userID {
+		return nil, mongoTLC.ACCESS_DENIED
+	}
+
+	if len(service.Photos) >= mongoTLC.MAX_SERVICE_PHOTOS {
+		return nil, mongoTLC.TOO_MANY_PHOTOS
+	}
+
+	decisions, err := ucase.images.Check(imageField{domain.TargetServicePhoto, &photo.Image})
+	if err != nil {
+		return nil, err
+	}
+
+	if photo.Image == "" {
+		return ucase.withholdServicePhoto(userID, serviceID, decisions)
+	}
+
+	photoID, err := ucase.serviceRepo.AddServicePhoto(userID, serviceID, photo)
+	if err != nil {
+		return nil, err
+	}
+
+	decisions[0].upload.ImageID = photoID
+	_, err = ucase.images.Track(userID, serviceID, decisions)
+	if err != nil {
+		return nil, err
+	}
+
+	photoIDStruct := &domain.ApiServicePhoto{
+		PhotoID: photoID,
+	}
+
+	return photoIDStruct, nil
+}
+
+// withholdServicePhoto queues a photo that may not be published yet. Once
+// approved it is added to the gallery under the returned ID.
+func (ucase *ServiceUsecase) withholdServicePhoto(userID, serviceID string, decisions []*imageDecision) (*domain.ApiServicePhoto, error) {
+	imageIDs, err := ucase.images.Track(userID, serviceID, decisions)
+	if err != nil {
+		return nil, err
+	}
+
+	photoIDStruct := &domain.ApiServicePhoto{
+		PhotoID: imageIDs[0],
+	}
+
+	return photoIDStruct, nil
+}
+
+func (ucase *ServiceUsecase) DeleteServicePhoto(userID, serviceID, photoID string) error {
+	return ucase.serviceRepo.DeleteServicePhoto(userID, serviceID, photoID)
+}
+
+func (ucase *ServiceUsecase) SetServiceCover(userID, serviceID, photoID string) error {
+	return ucase.serviceRepo.SetServiceCover(userID, serviceID, photoID)
+}
+
+func (ucase *ServiceUsecase) ReorderServicePhotos(userID, serviceID string, order *domain.ServicePhotoOrder) error {
+	return ucase.serviceRepo.ReorderServicePhotos(userID, serviceID, order.PhotoIDs)
+}
+
+func (ucase *ServiceUsecase) Autocomplete(input string, limit int64) (*domain.Suggestions, error) {
+	if limit < 0 {
+		return nil, POSITIVE_NUMBER_REQUIRED
+	}
+
+	return ucase.serviceRepo.Autocomplete(strings.TrimSpace(input), limit)
+}
+
+// GetFeed recommends services for the animals of the user's pets. The feed of
+// a user without pets falls back to the most common animals.
+func (ucase *ServiceUsecase) GetFeed(userID string, page, pageSize int64) (*domain.Feed, error) {
+	if page < 0 || pageSize < 0 {
+		return nil, INVALID_SEARCH_PAGE
+	}
+
+	animals, err := ucase.getPetAnimals(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	personalized := len(animals) != 0
+	if !personalized {
+		topAnimals, err := ucase.petRepo.GetTopAnimals(FEED_TOP_ANIMALS)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, animal := range topAnimals {
+			animals = append(animals, mongoTLC.NormalizeAnimal(animal))
+		}
+	}
+
+	items, err := ucase.serviceRepo.GetFeed(userID, animals, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]*domain.ApiService, len(items))
+	for i, item := range items {
+		services[i] = item.Service
+
+		if !personalized && item.Reason == domain.ReasonYourPets {
+			item.Reason = domain.ReasonPopularAnimals
+		}
+
+		if len(item.Service.PetIDs) == 0 {
+			item.Service.PetIDs = []string{}
+		}
+
+		avatar, err := ucase.userRepo.GetAvatarBytes(item.Service.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		item.Service.UserImage = base64.StdEncoding.EncodeToString(avatar)
+	}
+
+	err = ucase.markFavorites(userID, services)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Feed{Items: items, Personalized: personalized}, nil
+}
+
+// getPetAnimals returns the distinct animals of the user's pets.
+func (ucase *ServiceUsecase) getPetAnimals(userID string) ([]string, error) {
+	petIDs, err := ucase.userRepo.GetUserPets(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	animals := []string{}
+	for _, petID := range petIDs {
+		pet, err := ucase.petRepo.GetPetInfoIncludingHidden(petID)
+		if errors.Is(err, mongoTLC.NOT_FOUND) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		typeOfAnimal := pet.TypeOfAnimal
+		if pet.AnimalID != "" {
+			typeOfAnimal = pet.AnimalID
+		}
+
+		animal := mongoTLC.NormalizeAnimal(typeOfAnimal)
+		if animal != "" && !slices.Contains(animals, animal) {
+			animals = append(animals, animal)
+		}
+	}
+
+	return animals, nil
+}
+
+func (ucase *ServiceUsecase) GetSimilarServices(serviceID string, limit int64, viewerID string) ([]*domain.SimilarService, error) {
+	if limit < 0 {
+		return nil, POSITIVE_NUMBER_REQUIRED
+	}
+
+	similar, err := ucase.serviceRepo.GetSimilarServices(serviceID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]*domain.ApiService, len(similar))
+	for i, item := range similar {
+		services[i] = item.Service
+
+		if len(item.Service.PetIDs) == 0 {
+			item.Service.PetIDs = []string{}
+		}
+
+		avatar, err := ucase.userRepo.GetAvatarBytes(item.Service.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		item.Service.UserImage = base64.StdEncoding.EncodeToString(avatar)
+	}
+
+	err = ucase.markFavorites(viewerID, services)
+	if err != nil {
+		return nil, err
+	}
+
+	return similar, nil
 }
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"mainService/internal/domain"
+	"mainService/pkg/resilience"
+)
+
+type IStatusUsecase interface {
+	GetIntegrationsStatus() []*domain.IntegrationStatus
+}
+
+type StatusUsecase struct {
+	guards []*resilience.Guard
+}
+
+func NewStatusUsecase(guards ...*resilience.Guard) IStatusUsecase {
+	return &StatusUsecase{
+		guards: guards,
+	}
+}
+
+func (ucase *StatusUsecase) GetIntegrationsStatus() []*domain.IntegrationStatus {
+	statuses := make([]*domain.IntegrationStatus, len(ucase.guards))
+	for i, guard := range ucase.guards {
+		status := guard.Status()
+
+		statuses[i] = &domain.IntegrationStatus{
+			Name:                status.Name,
+			State:               string(status.State),
+			FailMode:            string(status.FailMode),
+			ConsecutiveFailures: status.ConsecutiveFailures,
+			LastError:           status.LastError,
+		}
+
+		if !status.RetryAt.IsZero() {
+			statuses[i].RetryAt = &status.RetryAt
+		}
+	}
+
+	return statuses
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"mainService/internal/repository/mongoTLC"
+	"mainService/pkg/swearWordsDetector"
+)
+
+type swearWordSource struct {
+	swearWordRepo mongoTLC.ISwearWordRepository
+}
+
+// NewSwearWordSource exposes the words added by moderators to the detector.
+func NewSwearWordSource(swearWordRepository mongoTLC.ISwearWordRepository) swearWordsDetector.Source {
+	return &swearWordSource{
+		swearWordRepo: swearWordRepository,
+	}
+}
+
+func (src *swearWordSource) Load() ([]swearWordsDetector.Entry, error) {
+	words, err := src.swearWordRepo.GetSwearWords()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]swearWordsDetector.Entry, len(words))
+	for i, word := range words {
+		entries[i] = swearWordsDetector.Entry{
+			Word:     word.Word,
+			Language: word.Language,
+			Severity: swearWordsDetector.Severity(word.Severity),
+			Allowed:  word.Allowed,
+		}
+	}
+
+	return entries, nil
+}
+
+func (src *swearWordSource) Version() (string, error) {
+	return src.swearWordRepo.GetSwearWordsVersion()
+}
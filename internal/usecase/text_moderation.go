@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"mainService/configs"
+	"mainService/internal/domain"
+	"mainService/internal/repository/mongoTLC"
+	"mainService/pkg/serverErrors"
+	"mainService/pkg/spamDetector"
+	"mainService/pkg/swearWordsDetector"
+)
+
+type TextAction string
+
+const (
+	// TextReject refuses the input naming the offending fields.
+	TextReject TextAction = "reject"
+	// TextMask replaces the offending parts with asterisks.
+	TextMask TextAction = "mask"
+	// TextFlag lets the input through and tells moderators about it.
+	TextFlag TextAction = "flag"
+)
+
+func isTextAction(action TextAction) bool {
+	return action == TextReject || action == TextMask || action == TextFlag
+}
+
+// TextFieldPolicy tells what to do with swear words and spam found in a
+// field. An empty action turns the check off.
+type TextFieldPolicy struct {
+	Swears TextAction
+	Spam   TextAction
+}
+
+// ParseTextPolicy reads a policy written as "swears:reject,spam:flag".
+func ParseTextPolicy(spec string) (TextFieldPolicy, error) {
+	policy := TextFieldPolicy{}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		check, action, found := strings.Cut(part, ":")
+		textAction := TextAction(strings.TrimSpace(action))
+		if !found || !isTextAction(textAction) {
+			return TextFieldPolicy{}, BAD_TEXT_POLICY
+		}
+
+		switch strings.TrimSpace(check) {
+		case "swears":
+			policy.Swears = textAction
+		case "spam":
+			policy.Spam = textAction
+		default:
+			return TextFieldPolicy{}, BAD_TEXT_POLICY
+		}
+	}
+
+	return policy, nil
+}
+
+// TextInput is a user input to be moderated in place.
+type TextInput struct {
+	Field domain.TextField
+	Value *string
+}
+
+// FlaggedText is a field let through that moderators have to look at.
+type FlaggedText struct {
+	Field  domain.TextField
+	Reason string
+}
+
+// TextModerator checks every user text against the policy of its field.
+type TextModerator interface {
+	// Check rejects the inputs or masks them in place as the policy says.
+	// The flagged fields are returned to be passed to Flag once the subject
+	// is stored.
+	Check(inputs ...TextInput) ([]FlaggedText, error)
+	Flag(subjectID string, flagged []FlaggedText) error
+}
+
+type textModerator struct {
+	detector         *swearWordsDetector.Detector
+	notificationRepo mongoTLC.INotificationRepository
+	policies         map[domain.TextField]TextFieldPolicy
+}
+
+func NewTextModerator(
+	detector *swearWordsDetector.Detector,
+	notificationRepository mongoTLC.INotificationRepository,
+	policies map[domain.TextField]TextFieldPolicy,
+) TextModerator {
+	return &textModerator{
+		detector:         detector,
+		notificationRepo: notificationRepository,
+		policies:         policies,
+	}
+}
+
+func (m *textModerator) Check(inputs ...TextInput) ([]FlaggedText, error) {
+	swearFields := []swearWordsDetector.Field{}
+	checked := []TextInput{}
+	for _, input := range inputs {
+		if m.policies[input.Field].Swears != "" && *input.Value != "" {
+			swearFields = append(swearFields, swearWordsDetector.Field{Name: string(input.Field), Value: *input.Value})
+			checked = append(checked, input)
+		}
+	}
+
+	swearsToMask := map[int][]swearWordsDetector.Match{}
+	rejectedForSwears, flagged := []string{}, []FlaggedText{}
+	for _, match := range m.detector.FindInFields(swearFields...) {
+		field := checked[match.Input].Field
+
+		action := m.policies[field].Swears
+		// words of the flag severity never get the input rejected
+		if match.Severity == swearWordsDetector.SeverityFlag {
+			action = TextFlag
+		}
+
+		switch action {
+		case TextReject:
+			rejectedForSwears = appendName(rejectedForSwears, field.Name())
+		case TextMask:
+			swearsToMask[match.Input] = append(swearsToMask[match.Input], match)
+		case TextFlag:
+			flagged = appendFlagged(flagged, FlaggedText{Field: field, Reason: "swear words"})
+		}
+	}
+
+	if len(rejectedForSwears) != 0 {
+		return nil, serverErrors.NewFieldsError(serverErrors.SWEAR_WORDS_ERROR, rejectedForSwears...)
+	}
+
+	rejectedForSpam := []string{}
+	spamToMask := []int{}
+	for i, input := range inputs {
+		action := m.policies[input.Field].Spam
+		if action == "" {
+			continue
+		}
+
+		matches := spamDetector.Find(*input.Value)
+		if len(matches) == 0 {
+			continue
+		}
+
+		switch action {
+		case TextReject:
+			rejectedForSpam = append(rejectedForSpam, input.Field.Name())
+		case TextMask:
+			spamToMask = append(spamToMask, i)
+		case TextFlag:
+			flagged = appendFlagged(flagged, FlaggedText{Field: input.Field, Reason: "spam (" + string(matches[0].Kind) + ")"})
+		}
+	}
+
+	if len(rejectedForSpam) != 0 {
+		return nil, serverErrors.NewFieldsError(serverErrors.SPAM_ERROR, rejectedForSpam...)
+	}
+
+	// Nothing is changed until the input is known to be accepted.
+	for i, matches := range swearsToMask {
+		*checked[i].Value = swearWordsDetector.MaskMatches(*checked[i].Value, matches)
+	}
+
+	// masking swears moves the text around, so spam is looked for again in
+	// the masked value
+	for _, i := range spamToMask {
+		*inputs[i].Value = spamDetector.Mask(*inputs[i].Value, spamDetector.Find(*inputs[i].Value))
+	}
+
+	return flagged, nil
+}
+
+// appendName adds the name unless it is already the last one: matches come
+// grouped by field.
+func appendName(names []string, name string) []string {
+	if len(names) != 0 && names[len(names)-1] == name {
+		return names
+	}
+
+	return append(names, name)
+}
+
+func appendFlagged(flagged []FlaggedText, text FlaggedText) []FlaggedText {
+	if len(flagged) != 0 && flagged[len(flagged)-1] == text {
+		return flagged
+	}
+
+	return append(flagged, text)
+}
+
+// Flag tells moderators which fields of the subject have to be looked at.
+func (m *textModerator) Flag(subjectID string, flagged []FlaggedText) error {
+	if len(flagged) == 0 {
+		return nil
+	}
+
+	reasons := make([]string, len(flagged))
+	for i, text := range flagged {
+		reasons[i] = fmt.Sprintf("%s: %s", text.Field, text.Reason)
+	}
+
+	message := subjectID + " has texts to review: " + strings.Join(reasons, ", ")
+	for moderatorID := range configs.ModeratorIDs {
+		err := m.notificationRepo.AddNotification(moderatorID, domain.NotificationTextFlagged, message, subjectID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
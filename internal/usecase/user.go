@@ -7,8 +7,6 @@ import (
 	"mainService/internal/repository/redisTLC"
 
 	"mainService/pkg/nsfwFilter"
-	"mainService/pkg/serverErrors"
-	"mainService/pkg/swearWordsDetector"
 
 	"github.com/google/uuid"
 )
@@ -17,7 +15,7 @@ type IUserUsecase interface {
 	Login(cred *domain.LoginCredentials) (*domain.LoginResponse, error)
 	AddUser(newUser *domain.ApiUserInfo) (*domain.LoginResponse, error)
 	UpdateUser(userID string, updInfo *domain.ApiUserUpdate) error
-	GetUserInfo(userID string) (*domain.ApiUserInfo, error)
+	GetUserInfo(userID, viewerID string) (*domain.ApiUserInfo, error)
 	GetUserAvatar(userID string) (string, error)
 	GetUserPets(userID string) (*domain.PetIDList, error)
 	AddPet(userID string, petInfo *domain.ApiPetInfo) (*domain.ApiPetInfo, error)
@@ -28,72 +26,37 @@ type IUserUsecase interface {
 type UserUsecase struct {
 	userRepo    mongoTLC.IUserRepository
 	sessionRepo redisTLC.IAuthRepository
+	images      *imageGate
+	texts       TextModerator
 }
 
 func NewUserUsecase(
 	userRepository mongoTLC.IUserRepository,
 	sessionRepository redisTLC.IAuthRepository,
+	imageHashRepository mongoTLC.IImageHashRepository,
+	imageModerator nsfwFilter.ImageModerator,
+	moderationQueue *ImageModerationQueue,
+	textModerator TextModerator,
 ) IUserUsecase {
 	return &UserUsecase{
 		userRepo:    userRepository,
 		sessionRepo: sessionRepository,
+		images:      newImageGate(imageHashRepository, imageModerator, moderationQueue),
+		texts:       textModerator,
 	}
 }
 
-func (ucase *UserUsecase) ValidateImagesForNSFW(avatar, backImage string) error {
-	imagesToValidate := []string{}
-
-	if avatar != "" {
-		imagesToValidate = append(imagesToValidate, avatar)
-	}
-	if backImage != "" {
-		imagesToValidate = append(imagesToValidate, backImage)
-	}
-
-	if len(imagesToValidate) != 0 {
-		results := nsfwFilter.RunInParallel(imagesToValidate...)
-
-		if avatar != "" {
-			userImageRes := results[0]
-			if userImageRes.ProcessingErr != nil {
-				return userImageRes.ProcessingErr
-			}
-
-			if !userImageRes.Inf.IsSafe {
-				return serverErrors.NSFW_CONTENT_AVATAR_ERROR
-			}
-
-			if backImage != "" {
-				backImageRes := results[1]
-				if backImageRes.ProcessingErr != nil {
-					return backImageRes.ProcessingErr
-				}
-
-				if !backImageRes.Inf.IsSafe {
-					return serverErrors.NSFW_CONTENT_BACK_IMAGE_ERROR
-				}
-			}
-		} else if backImage != "" {
-			backImageRes := results[0]
-			if backImageRes.ProcessingErr != nil {
-				return backImageRes.ProcessingErr
-			}
-
-			if !backImageRes.Inf.IsSafe {
-				return serverErrors.NSFW_CONTENT_BACK_IMAGE_ERROR
-			}
-		}
-	}
-
-	return nil
-}
-
 func (ucase *UserUsecase) Login(cred *domain.LoginCredentials) (*domain.LoginResponse, error) {
 	userID, err := ucase.userRepo.CheckUser(cred)
 	if err != nil {
 		return nil, err
 	}
 
+	err = checkNotSuspended(ucase.userRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	sessionID := uuid.NewString()
 
 	err = ucase.sessionRepo.AddSession(sessionID, userID)
@@ -105,14 +68,21 @@ func (ucase *UserUsecase) Login(cred *domain.LoginCredentials) (*domain.LoginRes
 }
 
 func (ucase *UserUsecase) AddUser(newUser *domain.ApiUserInfo) (*domain.LoginResponse, error) {
-	validErr := ucase.ValidateImagesForNSFW(newUser.UserImage, newUser.UserBackImage)
+	decisions, validErr := ucase.images.Check(
+		imageField{domain.TargetAvatar, &newUser.UserImage},
+		imageField{domain.TargetBackground, &newUser.UserBackImage},
+	)
 	if validErr != nil {
 		return nil, validErr
 	}
 
-	containsSwearWords := swearWordsDetector.DetectInMultipleInputs(newUser.Login, newUser.Username, newUser.Contacts)
-	if containsSwearWords {
-		return nil, serverErrors.SWEAR_WORDS_ERROR
+	flaggedTexts, textErr := ucase.texts.Check(
+		TextInput{domain.FieldUserLogin, &newUser.Login},
+		TextInput{domain.FieldUserUsername, &newUser.Username},
+		TextInput{domain.FieldUserContacts, &newUser.Contacts},
+	)
+	if textErr != nil {
+		return nil, textErr
 	}
 
 	verifStatus := ucase.userRepo.ValidateLogin(newUser.Login)
@@ -129,6 +99,16 @@ func (ucase *UserUsecase) AddUser(newUser *domain.ApiUserInfo) (*domain.LoginRes
 		return nil, err
 	}
 
+	_, err = ucase.images.Track(userID, userID, decisions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ucase.texts.Flag(userID, flaggedTexts)
+	if err != nil {
+		return nil, err
+	}
+
 	sessionID := uuid.NewString()
 
 	err = ucase.sessionRepo.AddSession(sessionID, userID)
@@ -140,14 +120,25 @@ func (ucase *UserUsecase) AddUser(newUser *domain.ApiUserInfo) (*domain.LoginRes
 }
 
 func (ucase *UserUsecase) UpdateUser(userID string, updInfo *domain.ApiUserUpdate) error {
-	validErr := ucase.ValidateImagesForNSFW(updInfo.UserImage, updInfo.UserBackImage)
+	if err := checkNotSuspended(ucase.userRepo, userID); err != nil {
+		return err
+	}
+
+	decisions, validErr := ucase.images.Check(
+		imageField{domain.TargetAvatar, &updInfo.UserImage},
+		imageField{domain.TargetBackground, &updInfo.UserBackImage},
+	)
 	if validErr != nil {
 		return validErr
 	}
 
-	containsSwearWords := swearWordsDetector.DetectInMultipleInputs(updInfo.Login, updInfo.Username, updInfo.Contacts)
-	if containsSwearWords {
-		return serverErrors.SWEAR_WORDS_ERROR
+	flaggedTexts, textErr := ucase.texts.Check(
+		TextInput{domain.FieldUserLogin, &updInfo.Login},
+		TextInput{domain.FieldUserUsername, &updInfo.Username},
+		TextInput{domain.FieldUserContacts, &updInfo.Contacts},
+	)
+	if textErr != nil {
+		return textErr
 	}
 
 	if updInfo.Login != "" {
@@ -179,11 +170,23 @@ func (ucase *UserUsecase) UpdateUser(userID string, updInfo *domain.ApiUserUpdat
 		return err
 	}
 
-	return nil
+	_, err = ucase.images.Track(userID, userID, decisions)
+	if err != nil {
+		return err
+	}
+
+	return ucase.texts.Flag(userID, flaggedTexts)
 }
 
-func (ucase *UserUsecase) GetUserInfo(userID string) (*domain.ApiUserInfo, error) {
-	uInfo, err := ucase.userRepo.GetUserInfo(userID)
+// GetUserInfo hides the profiles hidden by moderators from everyone but
+// their owner.
+func (ucase *UserUsecase) GetUserInfo(userID, viewerID string) (*domain.ApiUserInfo, error) {
+	getUserInfo := ucase.userRepo.GetUserInfo
+	if viewerID == userID {
+		getUserInfo = ucase.userRepo.GetUserInfoIncludingHidden
+	}
+
+	uInfo, err := getUserInfo(userID)
 	if err != nil {
 		return nil, err
 	}
@@ -224,14 +227,22 @@ func (ucase *UserUsecase) GetUserPets(userID string) (*domain.PetIDList, error)
 }
 
 func (ucase *UserUsecase) AddPet(userID string, petInfo *domain.ApiPetInfo) (*domain.ApiPetInfo, error) {
-	validErr := ucase.ValidateImagesForNSFW(petInfo.PetAvatar, "")
+	if err := checkNotSuspended(ucase.userRepo, userID); err != nil {
+		return nil, err
+	}
+
+	decisions, validErr := ucase.images.Check(imageField{domain.TargetPetAvatar, &petInfo.PetAvatar})
 	if validErr != nil {
 		return nil, validErr
 	}
 
-	containsSwearWords := swearWordsDetector.DetectInMultipleInputs(petInfo.Info, petInfo.Name, petInfo.TypeOfAnimal)
-	if containsSwearWords {
-		return nil, serverErrors.SWEAR_WORDS_ERROR
+	flaggedTexts, textErr := ucase.texts.Check(
+		TextInput{domain.FieldPetInfo, &petInfo.Info},
+		TextInput{domain.FieldPetName, &petInfo.Name},
+		TextInput{domain.FieldPetTypeOfAnimal, &petInfo.TypeOfAnimal},
+	)
+	if textErr != nil {
+		return nil, textErr
 	}
 
 	petID, err := ucase.userRepo.AddPet(userID, petInfo)
@@ -239,6 +250,16 @@ func (ucase *UserUsecase) AddPet(userID string, petInfo *domain.ApiPetInfo) (*do
 		return nil, err
 	}
 
+	_, err = ucase.images.Track(userID, petID, decisions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ucase.texts.Flag(petID, flaggedTexts)
+	if err != nil {
+		return nil, err
+	}
+
 	petIDStruct := &domain.ApiPetInfo{
 		PetID: petID,
 	}
@@ -256,14 +277,22 @@ func (ucase *UserUsecase) DeletePet(userID, petID string) error {
 }
 
 func (ucase *UserUsecase) UpdatePet(userID, petID string, updInfo *domain.ApiPetUpdate) error {
-	validErr := ucase.ValidateImagesForNSFW(updInfo.PetAvatar, "")
+	if err := checkNotSuspended(ucase.userRepo, userID); err != nil {
+		return err
+	}
+
+	decisions, validErr := ucase.images.Check(imageField{domain.TargetPetAvatar, &updInfo.PetAvatar})
 	if validErr != nil {
 		return validErr
 	}
 
-	containsSwearWords := swearWordsDetector.DetectInMultipleInputs(updInfo.Info, updInfo.Name, updInfo.TypeOfAnimal)
-	if containsSwearWords {
-		return serverErrors.SWEAR_WORDS_ERROR
+	flaggedTexts, textErr := ucase.texts.Check(
+		TextInput{domain.FieldPetInfo, &updInfo.Info},
+		TextInput{domain.FieldPetName, &updInfo.Name},
+		TextInput{domain.FieldPetTypeOfAnimal, &updInfo.TypeOfAnimal},
+	)
+	if textErr != nil {
+		return textErr
 	}
 
 	err := ucase.userRepo.UpdatePet(userID, petID, updInfo)
@@ -271,5 +300,10 @@ func (ucase *UserUsecase) UpdatePet(userID, petID string, updInfo *domain.ApiPet
 		return err
 	}
 
-	return nil
+	_, err = ucase.images.Track(userID, petID, decisions)
+	if err != nil {
+		return err
+	}
+
+	return ucase.texts.Flag(petID, flaggedTexts)
 }
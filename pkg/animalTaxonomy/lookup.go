@@ -0,0 +1,157 @@
+package animalTaxonomy
+
+import (
+	"strings"
+	"unicode"
+
+	"mainService/pkg/textLanguage"
+)
+
+type synonym struct {
+	animal *Animal
+	stems  []string
+	text   string
+}
+
+var (
+	byID     = map[string]*Animal{}
+	byStems  = map[string]*Animal{}
+	synonyms = []*synonym{}
+)
+
+func init() {
+	for _, animal := range animals {
+		byID[animal.ID] = animal
+
+		for _, lang := range []string{LangEn, LangRu} {
+			for _, name := range animal.Names[lang] {
+				words := splitWords(name)
+				stems := stemWords(words)
+
+				key := strings.Join(stems, " ")
+				if _, taken := byStems[key]; !taken {
+					byStems[key] = animal
+				}
+
+				synonyms = append(synonyms, &synonym{animal: animal, stems: stems, text: strings.Join(words, " ")})
+			}
+		}
+	}
+}
+
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ReplaceAll(strings.ToLower(text), "ё", "е"), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func stemWords(words []string) []string {
+	stems := make([]string, len(words))
+	for i, word := range words {
+		stems[i] = textLanguage.Stem(word)
+	}
+
+	return stems
+}
+
+func Get(id string) (*Animal, bool) {
+	animal, found := byID[id]
+	return animal, found
+}
+
+func All() []*Animal {
+	return animals
+}
+
+// Canonical maps the words people call an animal by to its taxonomy entry,
+// whatever the case, word form or language. Texts with more words, like
+// "my siamese cat", take the longest name found in them, breeds winning
+// over species.
+func Canonical(text string) (*Animal, bool) {
+	if animal, found := byID[strings.TrimSpace(strings.ToLower(text))]; found {
+		return animal, true
+	}
+
+	stems := stemWords(splitWords(text))
+	if len(stems) == 0 {
+		return nil, false
+	}
+
+	if animal, found := byStems[strings.Join(stems, " ")]; found {
+		return animal, true
+	}
+
+	var best *synonym
+	for _, candidate := range synonyms {
+		if !containsRun(stems, candidate.stems) {
+			continue
+		}
+
+		if best == nil || isBetterMatch(candidate, best) {
+			best = candidate
+		}
+	}
+
+	if best == nil {
+		return nil, false
+	}
+
+	return best.animal, true
+}
+
+func isBetterMatch(candidate, best *synonym) bool {
+	isBreed, bestIsBreed := candidate.animal.Species != "", best.animal.Species != ""
+	if isBreed != bestIsBreed {
+		return isBreed
+	}
+
+	return len(candidate.stems) > len(best.stems)
+}
+
+// containsRun tells whether the words hold the run of words in a row.
+func containsRun(words, run []string) bool {
+	for start := 0; start+len(run) <= len(words); start++ {
+		matched := true
+		for i, word := range run {
+			if words[start+i] != word {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Lookup finds the animals having a name in any language starting with the
+// query, those whose name in lang matches going first. An empty query finds
+// all of them.
+func Lookup(query, lang string, limit int) []*Animal {
+	prefix := strings.Join(splitWords(query), " ")
+
+	found := map[string]struct{}{}
+	var matched, others []*Animal
+	for _, candidate := range synonyms {
+		if _, seen := found[candidate.animal.ID]; seen || !strings.HasPrefix(candidate.text, prefix) {
+			continue
+		}
+		found[candidate.animal.ID] = struct{}{}
+
+		if names := candidate.animal.Names[lang]; len(names) != 0 && strings.HasPrefix(strings.Join(splitWords(names[0]), " "), prefix) {
+			matched = append(matched, candidate.animal)
+		} else {
+			others = append(others, candidate.animal)
+		}
+	}
+
+	result := append(matched, others...)
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result
+}
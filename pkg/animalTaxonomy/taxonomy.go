@@ -0,0 +1,181 @@
+package animalTaxonomy
+
+// Animal is a species or, when Species is set, a breed of it. Names holds
+// the words people call it in each language, the name to show going first.
+type Animal struct {
+	ID      string
+	Species string
+	Names   map[string][]string
+}
+
+const (
+	LangEn = "en"
+	LangRu = "ru"
+)
+
+// DefaultLang names the animals when there are no names in the language
+// asked for.
+const DefaultLang = LangEn
+
+// SpeciesID is the ID of the species of a breed, or of the animal itself.
+func (animal *Animal) SpeciesID() string {
+	if animal.Species != "" {
+		return animal.Species
+	}
+
+	return animal.ID
+}
+
+func (animal *Animal) Name(lang string) string {
+	if names := animal.Names[lang]; len(names) != 0 {
+		return names[0]
+	}
+
+	return animal.Names[DefaultLang][0]
+}
+
+// animals are looked up in this order, so the most common ones go first and
+// every breed goes after its species.
+var animals = []*Animal{
+	{ID: "cat", Names: map[string][]string{
+		LangEn: {"cat", "kitty", "kitten", "pussycat"},
+		LangRu: {"кошка", "кот", "котик", "котенок", "кошечка", "киса", "котэ"},
+	}},
+	{ID: "dog", Names: map[string][]string{
+		LangEn: {"dog", "puppy", "doggy", "hound"},
+		LangRu: {"собака", "пес", "песик", "щенок", "собачка", "псина"},
+	}},
+	{ID: "rabbit", Names: map[string][]string{
+		LangEn: {"rabbit", "bunny"},
+		LangRu: {"кролик", "крольчиха", "зайчик"},
+	}},
+	{ID: "hamster", Names: map[string][]string{
+		LangEn: {"hamster"},
+		LangRu: {"хомяк", "хомячок", "хомка"},
+	}},
+	{ID: "guinea_pig", Names: map[string][]string{
+		LangEn: {"guinea pig", "cavy"},
+		LangRu: {"морская свинка", "свинка"},
+	}},
+	{ID: "rat", Names: map[string][]string{
+		LangEn: {"rat"},
+		LangRu: {"крыса", "крыска", "крысенок"},
+	}},
+	{ID: "mouse", Names: map[string][]string{
+		LangEn: {"mouse", "mice"},
+		LangRu: {"мышь", "мышка", "мышонок"},
+	}},
+	{ID: "chinchilla", Names: map[string][]string{
+		LangEn: {"chinchilla"},
+		LangRu: {"шиншилла"},
+	}},
+	{ID: "ferret", Names: map[string][]string{
+		LangEn: {"ferret"},
+		LangRu: {"хорек", "фретка"},
+	}},
+	{ID: "hedgehog", Names: map[string][]string{
+		LangEn: {"hedgehog"},
+		LangRu: {"еж", "ежик"},
+	}},
+	{ID: "parrot", Names: map[string][]string{
+		LangEn: {"parrot"},
+		LangRu: {"попугай", "попугайчик"},
+	}},
+	{ID: "canary", Names: map[string][]string{
+		LangEn: {"canary"},
+		LangRu: {"канарейка", "кенар"},
+	}},
+	{ID: "fish", Names: map[string][]string{
+		LangEn: {"fish", "aquarium fish", "goldfish"},
+		LangRu: {"рыбка", "рыба", "аквариумная рыбка", "золотая рыбка"},
+	}},
+	{ID: "turtle", Names: map[string][]string{
+		LangEn: {"turtle", "tortoise"},
+		LangRu: {"черепаха", "черепашка"},
+	}},
+	{ID: "snake", Names: map[string][]string{
+		LangEn: {"snake", "python", "boa"},
+		LangRu: {"змея", "змейка", "питон", "удав", "уж"},
+	}},
+	{ID: "lizard", Names: map[string][]string{
+		LangEn: {"lizard", "gecko", "iguana", "bearded dragon"},
+		LangRu: {"ящерица", "геккон", "игуана", "агама"},
+	}},
+	{ID: "frog", Names: map[string][]string{
+		LangEn: {"frog", "toad"},
+		LangRu: {"лягушка", "жаба"},
+	}},
+	{ID: "horse", Names: map[string][]string{
+		LangEn: {"horse", "pony", "foal"},
+		LangRu: {"лошадь", "конь", "пони", "жеребенок"},
+	}},
+
+	{ID: "cat/british_shorthair", Species: "cat", Names: map[string][]string{
+		LangEn: {"british shorthair", "british"},
+		LangRu: {"британская короткошерстная", "британская", "британский", "британец"},
+	}},
+	{ID: "cat/scottish_fold", Species: "cat", Names: map[string][]string{
+		LangEn: {"scottish fold"},
+		LangRu: {"шотландская вислоухая", "вислоухая", "вислоухий", "шотландская", "шотландский"},
+	}},
+	{ID: "cat/siamese", Species: "cat", Names: map[string][]string{
+		LangEn: {"siamese"},
+		LangRu: {"сиамская", "сиамский"},
+	}},
+	{ID: "cat/maine_coon", Species: "cat", Names: map[string][]string{
+		LangEn: {"maine coon"},
+		LangRu: {"мейн-кун", "мейн кун", "мейнкун"},
+	}},
+	{ID: "cat/sphynx", Species: "cat", Names: map[string][]string{
+		LangEn: {"sphynx", "sphinx"},
+		LangRu: {"сфинкс"},
+	}},
+	{ID: "cat/persian", Species: "cat", Names: map[string][]string{
+		LangEn: {"persian"},
+		LangRu: {"персидская", "персидский", "перс"},
+	}},
+	{ID: "dog/labrador", Species: "dog", Names: map[string][]string{
+		LangEn: {"labrador retriever", "labrador"},
+		LangRu: {"лабрадор-ретривер", "лабрадор"},
+	}},
+	{ID: "dog/german_shepherd", Species: "dog", Names: map[string][]string{
+		LangEn: {"german shepherd", "alsatian"},
+		LangRu: {"немецкая овчарка", "овчарка"},
+	}},
+	{ID: "dog/husky", Species: "dog", Names: map[string][]string{
+		LangEn: {"siberian husky", "husky"},
+		LangRu: {"сибирский хаски", "хаски"},
+	}},
+	{ID: "dog/corgi", Species: "dog", Names: map[string][]string{
+		LangEn: {"welsh corgi", "corgi"},
+		LangRu: {"вельш-корги", "корги"},
+	}},
+	{ID: "dog/chihuahua", Species: "dog", Names: map[string][]string{
+		LangEn: {"chihuahua"},
+		LangRu: {"чихуахуа"},
+	}},
+	{ID: "dog/yorkshire_terrier", Species: "dog", Names: map[string][]string{
+		LangEn: {"yorkshire terrier", "yorkie"},
+		LangRu: {"йоркширский терьер", "йорк", "йорик"},
+	}},
+	{ID: "dog/pug", Species: "dog", Names: map[string][]string{
+		LangEn: {"pug"},
+		LangRu: {"мопс"},
+	}},
+	{ID: "dog/dachshund", Species: "dog", Names: map[string][]string{
+		LangEn: {"dachshund"},
+		LangRu: {"такса"},
+	}},
+	{ID: "dog/spitz", Species: "dog", Names: map[string][]string{
+		LangEn: {"pomeranian", "spitz"},
+		LangRu: {"шпиц", "померанский шпиц"},
+	}},
+	{ID: "parrot/budgerigar", Species: "parrot", Names: map[string][]string{
+		LangEn: {"budgerigar", "budgie"},
+		LangRu: {"волнистый попугай", "волнистый", "волнистик"},
+	}},
+	{ID: "parrot/cockatiel", Species: "parrot", Names: map[string][]string{
+		LangEn: {"cockatiel"},
+		LangRu: {"корелла", "нимфа"},
+	}},
+}
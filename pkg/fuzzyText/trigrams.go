@@ -0,0 +1,72 @@
+package fuzzyText
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// minWordLen leaves out prepositions and the like, whose trigrams would
+// match almost anything.
+const minWordLen = 2
+
+// Tokens returns the lowercased words of the text in their order. Ё is
+// spelled as е, as people rarely type it.
+func Tokens(text string) []string {
+	text = strings.ReplaceAll(strings.ToLower(text), "ё", "е")
+
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Words returns the tokens of the text long enough to be searched for,
+// without duplicates.
+func Words(text string) []string {
+	words := []string{}
+	for _, word := range Tokens(text) {
+		if len([]rune(word)) >= minWordLen && !slices.Contains(words, word) {
+			words = append(words, word)
+		}
+	}
+
+	return words
+}
+
+// Trigrams returns the sorted distinct trigrams of the words of the text.
+// Words are padded with two spaces in front and one behind, so that their
+// beginnings weigh more and short words still get trigrams:
+// "кот" gives "  к", " ко", "кот" and "от ".
+func Trigrams(text string) []string {
+	trigrams := []string{}
+	for _, word := range Words(text) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			trigrams = append(trigrams, string(runes[i:i+3]))
+		}
+	}
+
+	slices.Sort(trigrams)
+
+	return slices.Compact(trigrams)
+}
+
+// Similarity is the share of the trigrams of the query found in the text,
+// so that a short query matches a long title it is a part of.
+func Similarity(query, text string) float64 {
+	queryTrigrams := Trigrams(query)
+	if len(queryTrigrams) == 0 {
+		return 0
+	}
+
+	textTrigrams := Trigrams(text)
+
+	common := 0
+	for _, trigram := range queryTrigrams {
+		if _, found := slices.BinarySearch(textTrigrams, trigram); found {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(queryTrigrams))
+}
@@ -0,0 +1,205 @@
+package highlighter
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"mainService/pkg/fuzzyText"
+	"mainService/pkg/textLanguage"
+)
+
+const (
+	MarkStart = "<mark>"
+	MarkEnd   = "</mark>"
+	Ellipsis  = "…"
+)
+
+// Words with a typo in the query are highlighted too, as the search finds
+// them by trigrams.
+const (
+	minFuzzySimilarity = 0.7
+	minFuzzyWordLen    = 4
+)
+
+// Highlighter marks the words of texts matching the words of a search query
+// up to their endings. The texts are HTML escaped, so the marks are the only
+// markup of the result.
+type Highlighter struct {
+	queryStems map[string]struct{}
+}
+
+func New(query string) *Highlighter {
+	h := &Highlighter{
+		queryStems: map[string]struct{}{},
+	}
+
+	for _, word := range fuzzyText.Words(query) {
+		h.queryStems[textLanguage.Stem(word)] = struct{}{}
+	}
+
+	return h
+}
+
+type wordSpan struct {
+	start, end int
+	matched    bool
+}
+
+func (h *Highlighter) matches(word string) bool {
+	stem := textLanguage.Stem(strings.ToLower(word))
+	for queryStem := range h.queryStems {
+		if stemsMatch(queryStem, stem) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stemsMatch(queryStem, stem string) bool {
+	if queryStem == stem {
+		return true
+	}
+
+	return utf8.RuneCountInString(queryStem) >= minFuzzyWordLen && utf8.RuneCountInString(stem) >= minFuzzyWordLen &&
+		fuzzyText.Similarity(queryStem, stem) >= minFuzzySimilarity
+}
+
+// MatchesAll tells whether every word of the query is found in the texts.
+func (h *Highlighter) MatchesAll(texts ...string) bool {
+	stems := []string{}
+	for _, text := range texts {
+		for _, word := range fuzzyText.Words(text) {
+			stems = append(stems, textLanguage.Stem(word))
+		}
+	}
+
+	for queryStem := range h.queryStems {
+		found := false
+		for _, stem := range stems {
+			if stemsMatch(queryStem, stem) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// words returns the byte spans of the words of the text.
+func (h *Highlighter) words(text string) []wordSpan {
+	spans := []wordSpan{}
+
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWordRune && start < 0 {
+			start = i
+		} else if !isWordRune && start >= 0 {
+			spans = append(spans, wordSpan{start, i, h.matches(text[start:i])})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		spans = append(spans, wordSpan{start, len(text), h.matches(text[start:])})
+	}
+
+	return spans
+}
+
+// Highlight marks the matching words of the whole text.
+func (h *Highlighter) Highlight(text string) string {
+	return mark(text, h.words(text), 0, len(text))
+}
+
+// Snippet cuts out about maxRunes of the text around the place with the most
+// matching words and marks them. Found is false when nothing matches; the
+// snippet is the beginning of the text then.
+func (h *Highlighter) Snippet(text string, maxRunes int) (snippet string, found bool) {
+	spans := h.words(text)
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	// the window with the most matching words
+	bestFirst, bestMatches := 0, 0
+	for first := range spans {
+		matches := 0
+		for i := first; i < len(spans) && fits(text, spans, first, i, maxRunes); i++ {
+			if spans[i].matched {
+				matches++
+			}
+		}
+
+		if matches > bestMatches {
+			bestFirst, bestMatches = first, matches
+		}
+	}
+
+	// which is moved to start at its first match, keeping a third of the room
+	// for the words before it
+	for bestMatches > 0 && !spans[bestFirst].matched {
+		bestFirst++
+	}
+	firstMatch := bestFirst
+	for bestFirst > 0 && fits(text, spans, bestFirst-1, firstMatch, maxRunes/3) {
+		bestFirst--
+	}
+
+	bestLast := bestFirst
+	for bestLast+1 < len(spans) && fits(text, spans, bestFirst, bestLast+1, maxRunes) {
+		bestLast++
+	}
+
+	start, end := spans[bestFirst].start, spans[bestLast].end
+	if bestFirst == 0 {
+		start = 0
+	}
+	if bestLast == len(spans)-1 {
+		end = len(text)
+	}
+
+	snippet = mark(text, spans[bestFirst:bestLast+1], start, end)
+	if start > 0 {
+		snippet = Ellipsis + snippet
+	}
+	if end < len(text) {
+		snippet += Ellipsis
+	}
+
+	return snippet, bestMatches > 0
+}
+
+func fits(text string, spans []wordSpan, first, last, maxRunes int) bool {
+	return first == last || utf8.RuneCountInString(text[spans[first].start:spans[last].end]) <= maxRunes
+}
+
+// mark escapes text[start:end] wrapping the matching words into marks.
+func mark(text string, spans []wordSpan, start, end int) string {
+	var b strings.Builder
+
+	pos := start
+	for _, span := range spans {
+		if !span.matched || span.start < start || span.end > end {
+			continue
+		}
+
+		b.WriteString(html.EscapeString(text[pos:span.start]))
+		b.WriteString(MarkStart)
+		b.WriteString(html.EscapeString(text[span.start:span.end]))
+		b.WriteString(MarkEnd)
+		pos = span.end
+	}
+
+	b.WriteString(html.EscapeString(text[pos:end]))
+
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,83 @@
+package imageHash
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math/bits"
+	"strconv"
+)
+
+const (
+	hashWidth  = 9
+	hashHeight = 8
+)
+
+// DHash computes a 64-bit difference hash of the image: the picture is shrunk
+// to 9x8 grayscale cells and every bit tells whether a cell is brighter than
+// its right neighbour, so re-encoded, resized or slightly edited copies of the
+// same picture end up within a small Hamming distance from each other.
+func DHash(imageBytes []byte) (uint64, error) {
+	img, _, err := image.Decode(bytes.NewReader(imageBytes))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", DECODE_ERR, err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return 0, EMPTY_IMAGE_ERR
+	}
+
+	var cells [hashHeight][hashWidth]float64
+	for cy := 0; cy < hashHeight; cy++ {
+		y0 := bounds.Min.Y + cy*bounds.Dy()/hashHeight
+		y1 := max(bounds.Min.Y+(cy+1)*bounds.Dy()/hashHeight, y0+1)
+
+		for cx := 0; cx < hashWidth; cx++ {
+			x0 := bounds.Min.X + cx*bounds.Dx()/hashWidth
+			x1 := max(bounds.Min.X+(cx+1)*bounds.Dx()/hashWidth, x0+1)
+
+			var sum float64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					r, g, b, _ := img.At(x, y).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+				}
+			}
+
+			cells[cy][cx] = sum / float64((x1-x0)*(y1-y0))
+		}
+	}
+
+	var hash uint64
+	for cy := 0; cy < hashHeight; cy++ {
+		for cx := 0; cx < hashWidth-1; cx++ {
+			hash <<= 1
+			if cells[cy][cx] > cells[cy][cx+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash, nil
+}
+
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+func ToHex(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+func FromHex(hexHash string) (uint64, error) {
+	hash, err := strconv.ParseUint(hexHash, 16, 64)
+	if err != nil {
+		return 0, BAD_HASH_ERR
+	}
+
+	return hash, nil
+}
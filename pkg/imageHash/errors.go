@@ -0,0 +1,9 @@
+package imageHash
+
+import "fmt"
+
+var (
+	DECODE_ERR      = fmt.Errorf("failed to decode image")
+	EMPTY_IMAGE_ERR = fmt.Errorf("image has no pixels")
+	BAD_HASH_ERR    = fmt.Errorf("image hash must be a 64-bit hex string")
+)
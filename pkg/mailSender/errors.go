@@ -0,0 +1,8 @@
+package mailSender
+
+import "fmt"
+
+var (
+	EMPTY_FROM = fmt.Errorf("sender address must be non-empty")
+	BAD_HEADER = fmt.Errorf("line breaks are not allowed in the recipient and the subject")
+)
@@ -0,0 +1,74 @@
+package mailSender
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// Sender delivers plain text e-mails.
+type Sender interface {
+	Send(to, subject, body string) error
+}
+
+type SMTPConfig struct {
+	// Addr is host:port of the SMTP server.
+	Addr     string
+	From     string
+	User     string
+	Password string
+}
+
+type smtpSender struct {
+	conf SMTPConfig
+	auth smtp.Auth
+}
+
+// NewSMTPSender sends through an SMTP server, authenticating with PLAIN auth
+// when a user is given.
+func NewSMTPSender(conf SMTPConfig) (Sender, error) {
+	host, _, err := net.SplitHostPort(conf.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.From == "" {
+		return nil, EMPTY_FROM
+	}
+
+	sender := &smtpSender{conf: conf}
+	if conf.User != "" {
+		sender.auth = smtp.PlainAuth("", conf.User, conf.Password, host)
+	}
+
+	return sender, nil
+}
+
+func (s *smtpSender) Send(to, subject, body string) error {
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return BAD_HEADER
+	}
+
+	msg := "From: " + s.conf.From + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		strings.ReplaceAll(body, "\n", "\r\n")
+
+	return smtp.SendMail(s.conf.Addr, s.auth, s.conf.From, []string{to}, []byte(msg))
+}
+
+type logSender struct{}
+
+// NewLogSender prints the e-mails instead of sending them, for development.
+func NewLogSender() Sender {
+	return logSender{}
+}
+
+func (logSender) Send(to, subject, body string) error {
+	fmt.Printf("mail to %s: %s\n%s\n", to, subject, body)
+	return nil
+}
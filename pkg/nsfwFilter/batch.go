@@ -0,0 +1,151 @@
+package nsfwFilter
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+type batchImage struct {
+	ID    string `json:"id"`
+	Image string `json:"image"`
+}
+
+type batchMessage struct {
+	Images []batchImage `json:"images"`
+}
+
+type rawBatchInference struct {
+	ID string `json:"id"`
+	rawInference
+}
+
+type rawBatchReply struct {
+	Code    int                 `json:"code"`
+	StrErr  string              `json:"error,omitempty"`
+	Results []rawBatchInference `json:"results"`
+}
+
+type batchRequest struct {
+	image  string
+	result chan ParallelResult
+}
+
+// classify returns a channel receiving the verdict for the image. With a batch
+// window configured the image joins the next batch message, otherwise it is
+// sent on its own.
+func (c *Client) classify(base64Image string) <-chan ParallelResult {
+	result := make(chan ParallelResult, 1)
+
+	if c.config.BatchWindow <= 0 {
+		go func() {
+			inf, err := c.IsSafeForWork(base64Image)
+			result <- ParallelResult{Inf: inf, ProcessingErr: err}
+		}()
+
+		return result
+	}
+
+	select {
+	case c.batchQueue <- &batchRequest{image: base64Image, result: result}:
+	case <-c.closed:
+		result <- ParallelResult{ProcessingErr: CLIENT_CLOSED_ERR}
+	}
+
+	return result
+}
+
+// collectBatches groups requests arriving within the batch window, up to
+// MaxBatchSize images, and sends every group as one message.
+func (c *Client) collectBatches() {
+	for {
+		var first *batchRequest
+		select {
+		case first = <-c.batchQueue:
+		case <-c.closed:
+			return
+		}
+
+		batch := []*batchRequest{first}
+		timer := time.NewTimer(c.config.BatchWindow)
+
+	collect:
+		for len(batch) < c.config.MaxBatchSize {
+			select {
+			case req := <-c.batchQueue:
+				batch = append(batch, req)
+			case <-timer.C:
+				break collect
+			case <-c.closed:
+				timer.Stop()
+				for _, req := range batch {
+					req.result <- ParallelResult{ProcessingErr: CLIENT_CLOSED_ERR}
+				}
+				return
+			}
+		}
+		timer.Stop()
+
+		go c.sendBatch(batch)
+	}
+}
+
+func (c *Client) sendBatch(batch []*batchRequest) {
+	images := make([]batchImage, len(batch))
+	for i, req := range batch {
+		images[i] = batchImage{ID: strconv.Itoa(i), Image: req.image}
+	}
+
+	verdicts, err := c.classifyBatch(images)
+	for i, req := range batch {
+		if err != nil {
+			req.result <- ParallelResult{ProcessingErr: err}
+			continue
+		}
+
+		raw, ok := verdicts[images[i].ID]
+		if !ok {
+			req.result <- ParallelResult{ProcessingErr: MISSING_BATCH_RESULT_ERR}
+			continue
+		}
+
+		inf, parseErr := parseResults(raw)
+		req.result <- ParallelResult{Inf: inf, ProcessingErr: parseErr}
+	}
+}
+
+// classifyBatch sends the images in one message and returns raw verdicts by
+// image ID.
+func (c *Client) classifyBatch(images []batchImage) (map[string]rawInference, error) {
+	jsonBatch, err := json.Marshal(batchMessage{Images: images})
+	if err != nil {
+		return nil, errWithDetails(IMAGE_MARSHAL_ERR, err)
+	}
+
+	reply, err := c.call(jsonBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	rawReply := rawBatchReply{}
+	err = json.Unmarshal(reply, &rawReply)
+	if err != nil {
+		return nil, errWithDetails(RESPONSE_CONVERTION_ERR, err)
+	}
+
+	if rawReply.Code != OK {
+		_, err = parseResults(rawInference{Code: rawReply.Code, StrErr: rawReply.StrErr})
+		if err == nil {
+			err = WORKER_ERROR
+		}
+
+		return nil, err
+	}
+
+	verdicts := make(map[string]rawInference, len(rawReply.Results))
+	for _, res := range rawReply.Results {
+		verdicts[res.ID] = res.rawInference
+	}
+
+	return verdicts, nil
+}
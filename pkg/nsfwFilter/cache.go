@@ -0,0 +1,57 @@
+package nsfwFilter
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+type VerdictCache interface {
+	Get(key string) (Inference, bool, error)
+	Set(key string, inf Inference) error
+}
+
+var (
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
+)
+
+func CacheStats() (hits, misses int64) {
+	return cacheHits.Load(), cacheMisses.Load()
+}
+
+// ImageKey identifies an image by the SHA-256 of its decoded bytes, so the same
+// picture hits the cache regardless of how its base64 representation is padded.
+func ImageKey(base64Image string) (string, error) {
+	imageBytes, err := base64.StdEncoding.DecodeString(base64Image)
+	if err != nil {
+		return "", errWithDetails(IMAGE_DECODING_ERR, err)
+	}
+
+	sum := sha256.Sum256(imageBytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (c *Client) lookupCachedVerdict(key string) (Inference, bool) {
+	if c.config.Cache == nil || key == "" {
+		return Inference{}, false
+	}
+
+	inf, found, err := c.config.Cache.Get(key)
+	if err != nil || !found {
+		cacheMisses.Add(1)
+		return Inference{}, false
+	}
+
+	cacheHits.Add(1)
+	return inf, true
+}
+
+func (c *Client) storeVerdict(key string, inf Inference) {
+	if c.config.Cache == nil || key == "" {
+		return
+	}
+
+	_ = c.config.Cache.Set(key, inf)
+}
@@ -4,12 +4,14 @@ import "fmt"
 
 var (
 	IMAGE_MARSHAL_ERR         = fmt.Errorf("failed to marshal image into json")
+	IMAGE_DECODING_ERR        = fmt.Errorf("failed to decode base64 image")
 	RABBIT_CONNECT_ERR        = fmt.Errorf("failed to connect to RabbitMQ")
 	CHANNEL_OPENNING_ERR      = fmt.Errorf("failed to open a channel")
 	QUEUE_DECLARATION_ERR     = fmt.Errorf("failed to declare a queue")
 	CONSUMER_REGISTRATION_ERR = fmt.Errorf("failed to register a consumer")
 	MESSAGE_PUBLISHING_ERR    = fmt.Errorf("failed to publish a message")
 	RESPONSE_CONVERTION_ERR   = fmt.Errorf("failed to convert response body to json")
+	MISSING_BATCH_RESULT_ERR  = fmt.Errorf("batch response has no verdict for the image")
 
 	NO_RESPONSE_CODE        = fmt.Errorf("no response code has been returned")
 	ERR_CASTING_CODE        = fmt.Errorf("error while casting response code to type int")
@@ -23,8 +25,13 @@ var (
 	REQUEST_ERROR = fmt.Errorf("a bad request has been done, for more information see Inference.Err field")
 	WORKER_ERROR  = fmt.Errorf("nsfw validator error happened, for more information see Inference.Err field")
 
-	TIMEOUT_ERR = fmt.Errorf("a response has not been received before the timeout")
+	BAD_POLICY_ERR = fmt.Errorf("invalid nsfw policy: expected comma-separated 'bound:action' bands followed by an optional default action, actions are allow, flag, hide and reject")
 
+	TIMEOUT_ERR         = fmt.Errorf("a response has not been received before the timeout")
+	CONNECTION_LOST_ERR = fmt.Errorf("connection to RabbitMQ has been lost while waiting for a response")
+	CLIENT_CLOSED_ERR   = fmt.Errorf("nsfw filter client has been closed")
+
+	OK                    = 200
 	BAD_REQUEST           = 400
 	INTERNAL_SERVER_ERROR = 500
 )
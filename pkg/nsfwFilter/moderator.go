@@ -0,0 +1,124 @@
+package nsfwFilter
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// ImageModerator classifies base64 encoded images. Results are returned in the
+// order of the input images.
+type ImageModerator interface {
+	RunInParallel(base64Images ...string) []ParallelResult
+	Close() error
+}
+
+type stubModerator struct {
+	isSafe bool
+}
+
+// NewStubModerator returns a moderator that gives the same verdict to every
+// image without talking to the worker.
+func NewStubModerator(isSafe bool) ImageModerator {
+	return &stubModerator{
+		isSafe: isSafe,
+	}
+}
+
+func (m *stubModerator) RunInParallel(base64Images ...string) []ParallelResult {
+	results := make([]ParallelResult, len(base64Images))
+	for i := range base64Images {
+		results[i] = ParallelResult{
+			Inf: Inference{IsSafe: m.isSafe, Confidence: 1, Code: OK},
+		}
+	}
+
+	return results
+}
+
+func (m *stubModerator) Close() error {
+	return nil
+}
+
+type hashListModerator struct {
+	allowed       map[string]struct{}
+	denied        map[string]struct{}
+	defaultIsSafe bool
+}
+
+// NewHashListModerator returns a moderator deciding by SHA-256 image hashes
+// listed in the allow and deny files (one hex hash per line, '#' starts a
+// comment). Images found in neither list get the default verdict.
+func NewHashListModerator(allowFile, denyFile string, defaultIsSafe bool) (ImageModerator, error) {
+	allowed, err := readHashList(allowFile)
+	if err != nil {
+		return nil, err
+	}
+
+	denied, err := readHashList(denyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hashListModerator{
+		allowed:       allowed,
+		denied:        denied,
+		defaultIsSafe: defaultIsSafe,
+	}, nil
+}
+
+func readHashList(path string) (map[string]struct{}, error) {
+	hashes := map[string]struct{}{}
+	if path == "" {
+		return hashes, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line != "" {
+			hashes[line] = struct{}{}
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+func (m *hashListModerator) RunInParallel(base64Images ...string) []ParallelResult {
+	results := make([]ParallelResult, len(base64Images))
+	for i, image := range base64Images {
+		key, err := ImageKey(image)
+		if err != nil {
+			results[i] = ParallelResult{ProcessingErr: err}
+			continue
+		}
+
+		isSafe := m.defaultIsSafe
+		if _, ok := m.denied[key]; ok {
+			isSafe = false
+		} else if _, ok := m.allowed[key]; ok {
+			isSafe = true
+		}
+
+		results[i] = ParallelResult{
+			Inf: Inference{IsSafe: isSafe, Confidence: 1, Code: OK},
+		}
+	}
+
+	return results
+}
+
+func (m *hashListModerator) Close() error {
+	return nil
+}
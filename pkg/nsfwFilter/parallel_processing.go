@@ -1,15 +1,11 @@
 package nsfwFilter
 
-import (
-	"sync"
-)
-
 type ParallelResult struct {
 	Inf           Inference
 	ProcessingErr error
 }
 
-func RunInParallel(base64Images ...string) []ParallelResult {
+func (c *Client) RunInParallel(base64Images ...string) []ParallelResult {
 	/*runCommand := func(_wg *sync.WaitGroup, cmd command, inputImage string, job_number int, resSlice []ParallelResult) {
 		defer _wg.Done()
 
@@ -22,18 +18,28 @@ func RunInParallel(base64Images ...string) []ParallelResult {
 	}*/
 
 	results := make([]ParallelResult, len(base64Images))
-	wg := &sync.WaitGroup{}
+	keys := make([]string, len(base64Images))
+	replies := make([]<-chan ParallelResult, len(base64Images))
 	for job_number, image := range base64Images {
-		wg.Add(1)
-		go func(i int, img string) {
-			defer wg.Done()
+		keys[job_number], _ = ImageKey(image)
+		if inf, found := c.lookupCachedVerdict(keys[job_number]); found {
+			results[job_number] = ParallelResult{Inf: inf}
+			continue
+		}
 
-			res, err := IsSafeForWork(img)
-			results[i] = ParallelResult{Inf: res, ProcessingErr: err}
-		}(job_number, image)
+		replies[job_number] = c.classify(image)
 	}
 
-	wg.Wait()
+	for job_number, reply := range replies {
+		if reply == nil {
+			continue
+		}
+
+		results[job_number] = <-reply
+		if results[job_number].ProcessingErr == nil {
+			c.storeVerdict(keys[job_number], results[job_number].Inf)
+		}
+	}
 
 	return results
 }
@@ -0,0 +1,105 @@
+package nsfwFilter
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type Action string
+
+const (
+	// ActionAllow publishes the image.
+	ActionAllow Action = "allow"
+	// ActionFlag publishes the image and puts it into the review queue.
+	ActionFlag Action = "flag"
+	// ActionHide keeps the image hidden until a moderator approves it.
+	ActionHide Action = "hide"
+	// ActionReject refuses the image.
+	ActionReject Action = "reject"
+)
+
+func isAction(str Action) bool {
+	return str == ActionAllow || str == ActionFlag || str == ActionHide || str == ActionReject
+}
+
+// Band applies Action to images whose NSFW probability is below UpTo.
+type Band struct {
+	UpTo   float64
+	Action Action
+}
+
+// Policy maps the NSFW probability of an image to an action. Bands are
+// checked in ascending order; images above the last band get Otherwise.
+type Policy struct {
+	Bands     []Band
+	Otherwise Action
+}
+
+// ParsePolicy reads a policy written as "0.4:allow,0.6:flag,0.8:hide,reject",
+// where the trailing action without a bound applies to everything above.
+func ParsePolicy(spec string) (Policy, error) {
+	policy := Policy{Otherwise: ActionReject}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bound, action, hasBound := strings.Cut(part, ":")
+		if !hasBound {
+			policy.Otherwise = Action(strings.TrimSpace(bound))
+			if !isAction(policy.Otherwise) {
+				return Policy{}, BAD_POLICY_ERR
+			}
+
+			continue
+		}
+
+		upTo, err := strconv.ParseFloat(strings.TrimSpace(bound), 64)
+		if err != nil || upTo < 0 || upTo > 1 {
+			return Policy{}, BAD_POLICY_ERR
+		}
+
+		band := Band{UpTo: upTo, Action: Action(strings.TrimSpace(action))}
+		if !isAction(band.Action) {
+			return Policy{}, BAD_POLICY_ERR
+		}
+
+		policy.Bands = append(policy.Bands, band)
+	}
+
+	slices.SortFunc(policy.Bands, func(a, b Band) int {
+		if a.UpTo < b.UpTo {
+			return -1
+		} else if a.UpTo > b.UpTo {
+			return 1
+		}
+		return 0
+	})
+
+	return policy, nil
+}
+
+// NSFWProbability turns the worker's verdict, which carries the probability of
+// the winning class only, into the probability of the image being NSFW.
+func NSFWProbability(inf Inference) float64 {
+	if inf.IsSafe {
+		return 1 - inf.Confidence
+	}
+
+	return inf.Confidence
+}
+
+func (p Policy) Decide(inf Inference) Action {
+	probability := NSFWProbability(inf)
+
+	for _, band := range p.Bands {
+		if probability < band.UpTo {
+			return band.Action
+		}
+	}
+
+	return p.Otherwise
+}
@@ -0,0 +1,90 @@
+package nsfwFilter
+
+import (
+	"errors"
+
+	"mainService/pkg/resilience"
+)
+
+// IsTransient reports whether err is caused by the broker being unavailable
+// or slow rather than by the image. Errors the worker returns are not
+// retried, since it would fail on the same image again.
+func IsTransient(err error) bool {
+	for _, transient := range []error{
+		RABBIT_CONNECT_ERR,
+		CHANNEL_OPENNING_ERR,
+		QUEUE_DECLARATION_ERR,
+		CONSUMER_REGISTRATION_ERR,
+		MESSAGE_PUBLISHING_ERR,
+		TIMEOUT_ERR,
+		CONNECTION_LOST_ERR,
+	} {
+		if errors.Is(err, transient) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type resilientModerator struct {
+	moderator ImageModerator
+	guard     *resilience.Guard
+}
+
+// NewResilientModerator retries images that failed for transient reasons and
+// stops calling the moderator while the guard's breaker is open. When the
+// guard fails open, unavailable images get an unverified safe verdict.
+func NewResilientModerator(moderator ImageModerator, guard *resilience.Guard) ImageModerator {
+	return &resilientModerator{
+		moderator: moderator,
+		guard:     guard,
+	}
+}
+
+func (m *resilientModerator) RunInParallel(base64Images ...string) []ParallelResult {
+	results := make([]ParallelResult, len(base64Images))
+
+	unfinished := make([]int, len(base64Images))
+	for i := range base64Images {
+		unfinished[i] = i
+	}
+
+	err := m.guard.Do(func() error {
+		batch := make([]string, len(unfinished))
+		for j, i := range unfinished {
+			batch[j] = base64Images[i]
+		}
+
+		var lastErr error
+		failed := []int{}
+		for j, res := range m.moderator.RunInParallel(batch...) {
+			results[unfinished[j]] = res
+			if res.ProcessingErr != nil && IsTransient(res.ProcessingErr) {
+				failed = append(failed, unfinished[j])
+				lastErr = res.ProcessingErr
+			}
+		}
+
+		unfinished = failed
+		return lastErr
+	})
+
+	if err != nil {
+		for _, i := range unfinished {
+			if m.guard.FailsOpen() {
+				results[i] = ParallelResult{
+					Inf: Inference{IsSafe: true, Confidence: 1, Code: OK, Unverified: true},
+				}
+			} else {
+				results[i] = ParallelResult{ProcessingErr: err}
+			}
+		}
+	}
+
+	return results
+}
+
+func (m *resilientModerator) Close() error {
+	return m.moderator.Close()
+}
@@ -0,0 +1,77 @@
+package petAdviser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Advice struct {
+	Advice string `json:"advice"`
+	Animal string `json:"animal"`
+	Prompt string `json:"prompt"`
+}
+
+type errorResponse struct {
+	Error   string `json:"error"`
+	Details string `json:"details,omitempty"`
+}
+
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewClient(adviceURL string, timeout time.Duration) *Client {
+	return &Client{
+		url:        adviceURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *Client) GetAdvice(animal, prompt string) (*Advice, error) {
+	params := url.Values{}
+	params.Add("animal", animal)
+	params.Add("prompt", prompt)
+
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s?%s", c.url, params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", REQUEST_ERR, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", REQUEST_ERR, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := errorResponse{}
+		_ = json.Unmarshal(body, &errResp)
+
+		baseErr := SERVER_ERR
+		if resp.StatusCode < http.StatusInternalServerError {
+			baseErr = BAD_REQUEST
+		}
+
+		return nil, fmt.Errorf("%w: %d %s", baseErr, resp.StatusCode, errResp.Error)
+	}
+
+	advice := new(Advice)
+	err = json.Unmarshal(body, advice)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", RESPONSE_ERR, err)
+	}
+
+	return advice, nil
+}
+
+// IsTransient reports whether err means the adviser is unavailable rather
+// than the request being wrong.
+func IsTransient(err error) bool {
+	return errors.Is(err, REQUEST_ERR) || errors.Is(err, SERVER_ERR)
+}
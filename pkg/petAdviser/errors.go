@@ -0,0 +1,10 @@
+package petAdviser
+
+import "fmt"
+
+var (
+	REQUEST_ERR  = fmt.Errorf("failed to reach the adviser")
+	BAD_REQUEST  = fmt.Errorf("the adviser rejected the request")
+	SERVER_ERR   = fmt.Errorf("the adviser failed to give an advice")
+	RESPONSE_ERR = fmt.Errorf("failed to decode the adviser's response")
+)
@@ -0,0 +1,9 @@
+package resilience
+
+import "fmt"
+
+var (
+	UNAVAILABLE_ERR  = fmt.Errorf("integration is unavailable")
+	CIRCUIT_OPEN_ERR = fmt.Errorf("circuit breaker is open")
+	BAD_FAIL_MODE    = fmt.Errorf("invalid fail mode: must be 'open' or 'closed'")
+)
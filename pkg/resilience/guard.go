@@ -0,0 +1,114 @@
+package resilience
+
+import (
+	"fmt"
+	"time"
+)
+
+type FailMode string
+
+const (
+	// FailOpen lets callers fall back to a degraded result when the
+	// integration is unavailable.
+	FailOpen FailMode = "open"
+	// FailClosed makes callers report the outage as an error.
+	FailClosed FailMode = "closed"
+)
+
+func ParseFailMode(str string) (FailMode, error) {
+	mode := FailMode(str)
+	if mode != FailOpen && mode != FailClosed {
+		return "", BAD_FAIL_MODE
+	}
+
+	return mode, nil
+}
+
+type Config struct {
+	Name             string
+	Retry            RetryPolicy
+	FailureThreshold int
+	Cooldown         time.Duration
+	FailMode         FailMode
+	// Retryable tells outage errors from the ones caused by the request
+	// itself. Only the former are retried and counted by the breaker.
+	// All errors are retryable when it is nil.
+	Retryable func(error) bool
+}
+
+// Guard wraps calls to a single external integration with retries and a
+// circuit breaker.
+type Guard struct {
+	config  Config
+	breaker *Breaker
+}
+
+type Status struct {
+	Name                string
+	State               State
+	FailMode            FailMode
+	ConsecutiveFailures int
+	RetryAt             time.Time
+	LastError           string
+}
+
+func NewGuard(config Config) *Guard {
+	if config.Retry.Attempts < 1 {
+		config.Retry.Attempts = 1
+	}
+
+	return &Guard{
+		config:  config,
+		breaker: NewBreaker(config.FailureThreshold, config.Cooldown),
+	}
+}
+
+// Do runs fn until it succeeds, fails with a non-retryable error or runs out
+// of attempts. Outages are reported wrapped in UNAVAILABLE_ERR.
+func (g *Guard) Do(fn func() error) error {
+	if !g.breaker.allow() {
+		return fmt.Errorf("%w: %s: %w", UNAVAILABLE_ERR, g.config.Name, CIRCUIT_OPEN_ERR)
+	}
+
+	var err error
+	for attempt := 1; attempt <= g.config.Retry.Attempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(g.config.Retry.backoff(attempt - 1))
+		}
+
+		err = fn()
+		if err == nil {
+			g.breaker.record(nil)
+			return nil
+		}
+
+		if g.config.Retryable != nil && !g.config.Retryable(err) {
+			g.breaker.record(nil)
+			return err
+		}
+	}
+
+	g.breaker.record(err)
+	return fmt.Errorf("%w: %s: %w", UNAVAILABLE_ERR, g.config.Name, err)
+}
+
+func (g *Guard) FailsOpen() bool {
+	return g.config.FailMode == FailOpen
+}
+
+func (g *Guard) Status() Status {
+	state, failures, retryAt, lastErr := g.breaker.snapshot()
+
+	status := Status{
+		Name:                g.config.Name,
+		State:               state,
+		FailMode:            g.config.FailMode,
+		ConsecutiveFailures: failures,
+		RetryAt:             retryAt,
+	}
+	if lastErr != nil {
+		status.LastError = lastErr.Error()
+	}
+
+	return status
+}
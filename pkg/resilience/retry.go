@@ -0,0 +1,27 @@
+package resilience
+
+import (
+	"math/rand"
+	"time"
+)
+
+type RetryPolicy struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// backoff returns a random delay before the given retry (counting from 1),
+// bounded by an exponentially growing cap ("full jitter").
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	limit := p.BaseDelay << (retry - 1)
+	if limit <= 0 || (p.MaxDelay > 0 && limit > p.MaxDelay) {
+		limit = p.MaxDelay
+	}
+
+	if limit <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(limit)))
+}
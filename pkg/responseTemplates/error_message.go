@@ -2,6 +2,7 @@ package responseTemplates
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -9,11 +10,19 @@ import (
 )
 
 type errorToSend struct {
-	Message string `json:"message"`
+	Message string   `json:"message"`
+	Fields  []string `json:"fields,omitempty"`
 }
 
 func SendErrorMessage(w http.ResponseWriter, err error, statusCode int) error {
 	errResp := errorToSend{Message: err.Error()}
+
+	var fieldsErr *serverErrors.FieldsError
+	if errors.As(err, &fieldsErr) {
+		errResp.Message = fieldsErr.Err.Error()
+		errResp.Fields = fieldsErr.Fields
+	}
+
 	mess, marshalErr := json.Marshal(errResp)
 	if marshalErr != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -1,12 +1,36 @@
 package serverErrors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 var (
 	INTERNAL_SERVER_ERROR = fmt.Errorf("The server encountered a problem and could not process your request")
 	CAST_ERROR            = fmt.Errorf("error while casting a variable to another type")
 
-	SWEAR_WORDS_ERROR             = fmt.Errorf("some of your input fileds contain insulting words")
-	NSFW_CONTENT_AVATAR_ERROR     = fmt.Errorf("avatar image you trying to publish seems to be an explicit content and not suitable for work")
-	NSFW_CONTENT_BACK_IMAGE_ERROR = fmt.Errorf("back image you trying to publish seems to be an explicit content and not suitable for work")
+	SWEAR_WORDS_ERROR                = fmt.Errorf("some of your input fileds contain insulting words")
+	SPAM_ERROR                       = fmt.Errorf("some of your input fields contain phone numbers, links or repeated characters not allowed there")
+	NSFW_CONTENT_AVATAR_ERROR        = fmt.Errorf("avatar image you trying to publish seems to be an explicit content and not suitable for work")
+	NSFW_CONTENT_BACK_IMAGE_ERROR    = fmt.Errorf("back image you trying to publish seems to be an explicit content and not suitable for work")
+	NSFW_CONTENT_SERVICE_PHOTO_ERROR = fmt.Errorf("service photo you trying to publish seems to be an explicit content and not suitable for work")
+	BANNED_IMAGE_ERROR               = fmt.Errorf("image you trying to publish matches an image banned by moderators")
 )
+
+// FieldsError is an error caused by particular input fields.
+type FieldsError struct {
+	Err    error
+	Fields []string
+}
+
+func NewFieldsError(err error, fields ...string) error {
+	return &FieldsError{Err: err, Fields: fields}
+}
+
+func (e *FieldsError) Error() string {
+	return e.Err.Error() + ": " + strings.Join(e.Fields, ", ")
+}
+
+func (e *FieldsError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,141 @@
+package spamDetector
+
+import (
+	"regexp"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Kind string
+
+const (
+	KindPhone    Kind = "phone"
+	KindURL      Kind = "url"
+	KindEmail    Kind = "email"
+	KindRepeated Kind = "repeated"
+)
+
+// Match is a spam pattern found in an input, Start and End being byte offsets.
+type Match struct {
+	Kind  Kind
+	Start int
+	End   int
+}
+
+// MinRepeated is the length of a run of one character that counts as spam:
+// "soooo" is fine, "sooooo" and "!!!!!!" are not.
+const MinRepeated = 6
+
+// minPhoneDigits keeps prices, years and dates from being taken for phones.
+const minPhoneDigits = 7
+
+var (
+	phoneRegexp = regexp.MustCompile(`\+?\(?\d(?:[ \-()]*\d)+`)
+	emailRegexp = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9\-]+(?:\.[a-z0-9\-]+)*\.[a-z]{2,}`)
+	urlRegexp   = regexp.MustCompile(`(?i)(?:https?://|www\.)\S+|\b[a-z0-9][a-z0-9\-]*(?:\.[a-z0-9\-]+)*\.(?:com|net|org|ru|su|ua|by|kz|io|me|info|biz|xyz|site|online|shop|pro|app|ly|gg)\b(?:/\S*)?`)
+)
+
+// Find returns the non-overlapping spam patterns of the input from left to
+// right. Of overlapping ones the longest wins, so an e-mail isn't reported
+// as a URL too.
+func Find(input string) []Match {
+	found := []Match{}
+	for _, pattern := range []struct {
+		kind   Kind
+		regexp *regexp.Regexp
+	}{
+		{KindEmail, emailRegexp},
+		{KindURL, urlRegexp},
+		{KindPhone, phoneRegexp},
+	} {
+		for _, span := range pattern.regexp.FindAllStringIndex(input, -1) {
+			if pattern.kind == KindPhone && countDigits(input[span[0]:span[1]]) < minPhoneDigits {
+				continue
+			}
+
+			found = append(found, Match{Kind: pattern.kind, Start: span[0], End: span[1]})
+		}
+	}
+	found = append(found, findRepeated(input)...)
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Start != found[j].Start {
+			return found[i].Start < found[j].Start
+		}
+
+		return found[i].End > found[j].End
+	})
+
+	matches := []Match{}
+	prevEnd := 0
+	for _, match := range found {
+		if match.Start < prevEnd {
+			continue
+		}
+
+		matches = append(matches, match)
+		prevEnd = match.End
+	}
+
+	return matches
+}
+
+func Contains(input string) bool {
+	return len(Find(input)) != 0
+}
+
+func countDigits(str string) int {
+	count := 0
+	for _, r := range str {
+		if '0' <= r && r <= '9' {
+			count++
+		}
+	}
+
+	return count
+}
+
+// findRepeated finds runs of at least MinRepeated equal characters ignoring
+// case. Spaces are left alone since they are used for layout.
+func findRepeated(input string) []Match {
+	matches := []Match{}
+
+	runStart, runLen := 0, 0
+	var prev rune
+	for pos, r := range input {
+		r = unicode.ToLower(r)
+		if runLen != 0 && r == prev {
+			runLen++
+			continue
+		}
+
+		if runLen >= MinRepeated && !unicode.IsSpace(prev) {
+			matches = append(matches, Match{Kind: KindRepeated, Start: runStart, End: pos})
+		}
+
+		runStart, runLen, prev = pos, 1, r
+	}
+
+	if runLen >= MinRepeated && !unicode.IsSpace(prev) {
+		matches = append(matches, Match{Kind: KindRepeated, Start: runStart, End: len(input)})
+	}
+
+	return matches
+}
+
+// Mask replaces every character of the matches with an asterisk.
+func Mask(input string, matches []Match) string {
+	masked := []byte{}
+	prevEnd := 0
+	for _, match := range matches {
+		masked = append(masked, input[prevEnd:match.Start]...)
+		for i := utf8.RuneCountInString(input[match.Start:match.End]); i > 0; i-- {
+			masked = append(masked, '*')
+		}
+		prevEnd = match.End
+	}
+	masked = append(masked, input[prevEnd:]...)
+
+	return string(masked)
+}
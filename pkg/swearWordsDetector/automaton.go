@@ -0,0 +1,372 @@
+package swearWordsDetector
+
+import (
+	"slices"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// isSeparator reports whether r may be put between the letters of a word, as
+// [ .\-]* does in the regexp.
+func isSeparator(r rune) bool {
+	return r == ' ' || r == '.' || r == '-'
+}
+
+// foldRune returns the smallest rune of the case folding orbit of r.
+func foldRune(r rune) rune {
+	folded := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < folded {
+			folded = f
+		}
+	}
+
+	return folded
+}
+
+type acNode struct {
+	next map[rune]int32
+	fail int32
+	// words ending in this node, including the ones reached by fail links
+	words []int32
+}
+
+type candidate struct {
+	start int
+	word  int32
+}
+
+// letterRun is a letter of a word or, when repeats are collapsed, a run of
+// the same letter that has to be matched at least min times.
+type letterRun struct {
+	allowed []rune
+	min     int
+	repeat  bool
+}
+
+// Automaton is an Aho–Corasick automaton over normalized text: case is folded,
+// separators are dropped and every letter is replaced with its substitution
+// class, the union of all getSubstitution sets it takes part in. Classes are
+// coarser than the regexp's per-letter sets, so every candidate is verified
+// against the exact sets before it is reported.
+//
+// With collapseRepeats a letter repeated in the input stands for any number
+// of that letter in the word, not less than the word has: "fuuuck" matches
+// "fuck" and "asss" matches "ass", while "as" still doesn't.
+type Automaton struct {
+	classes map[rune]rune
+	nodes   []acNode
+	words   []string
+	// runs holds the folded runes allowed for every letter of every word
+	runs     [][]letterRun
+	normLens []int
+
+	collapseRepeats bool
+}
+
+func NewAutomaton(words []string, collapseRepeats bool) *Automaton {
+	a := &Automaton{
+		classes:         map[rune]rune{},
+		nodes:           []acNode{{next: map[rune]int32{}}},
+		words:           words,
+		runs:            make([][]letterRun, len(words)),
+		normLens:        make([]int, len(words)),
+		collapseRepeats: collapseRepeats,
+	}
+
+	parent := map[rune]rune{}
+	var find func(r rune) rune
+	find = func(r rune) rune {
+		p, ok := parent[r]
+		if !ok || p == r {
+			parent[r] = r
+			return r
+		}
+
+		root := find(p)
+		parent[r] = root
+		return root
+	}
+
+	for i, word := range words {
+		for _, char := range word {
+			allowed := []rune{foldRune(char)}
+			for _, sub := range getSubstitution(char) {
+				if folded := foldRune(sub); !slices.Contains(allowed, folded) {
+					allowed = append(allowed, folded)
+				}
+			}
+
+			for _, r := range allowed[1:] {
+				parent[find(r)] = find(allowed[0])
+			}
+
+			runs := a.runs[i]
+			if last := len(runs) - 1; collapseRepeats && !isSeparator(char) && last >= 0 &&
+				runs[last].repeat && slices.Equal(runs[last].allowed, allowed) {
+				runs[last].min++
+				continue
+			}
+
+			a.runs[i] = append(runs, letterRun{
+				allowed: allowed,
+				min:     1,
+				repeat:  collapseRepeats && !isSeparator(char),
+			})
+		}
+	}
+
+	for r := range parent {
+		a.classes[r] = find(r)
+	}
+
+	for i, word := range words {
+		node := int32(0)
+		prevClass := rune(-1)
+		for _, char := range word {
+			if isSeparator(char) {
+				continue
+			}
+
+			class := a.class(char)
+			if collapseRepeats && class == prevClass {
+				continue
+			}
+			prevClass = class
+
+			next, ok := a.nodes[node].next[class]
+			if !ok {
+				next = int32(len(a.nodes))
+				a.nodes = append(a.nodes, acNode{next: map[rune]int32{}})
+				a.nodes[node].next[class] = next
+			}
+
+			node = next
+			a.normLens[i]++
+		}
+
+		if node != 0 {
+			a.nodes[node].words = append(a.nodes[node].words, int32(i))
+		}
+	}
+
+	a.linkFailures()
+
+	return a
+}
+
+func (a *Automaton) linkFailures() {
+	queue := []int32{}
+	for _, child := range a.nodes[0].next {
+		queue = append(queue, child)
+	}
+
+	for len(queue) != 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for class, child := range a.nodes[node].next {
+			fail := a.nodes[node].fail
+			for fail != 0 && !a.hasNext(fail, class) {
+				fail = a.nodes[fail].fail
+			}
+
+			if next, ok := a.nodes[fail].next[class]; ok && next != child {
+				a.nodes[child].fail = next
+			}
+
+			a.nodes[child].words = append(a.nodes[child].words, a.nodes[a.nodes[child].fail].words...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+func (a *Automaton) hasNext(node int32, class rune) bool {
+	_, ok := a.nodes[node].next[class]
+	return ok
+}
+
+func (a *Automaton) class(r rune) rune {
+	folded := foldRune(r)
+	if class, ok := a.classes[folded]; ok {
+		return class
+	}
+
+	return folded
+}
+
+// candidates returns the words found in the normalized input with their
+// start offsets in the original one.
+func (a *Automaton) candidates(input string) []candidate {
+	found := []candidate{}
+	offsets := []int{}
+	node := int32(0)
+	prevClass := rune(-1)
+
+	for pos, r := range input {
+		if isSeparator(r) {
+			continue
+		}
+
+		class := a.class(r)
+		if a.collapseRepeats && class == prevClass {
+			continue
+		}
+		prevClass = class
+		offsets = append(offsets, pos)
+
+		for node != 0 && !a.hasNext(node, class) {
+			node = a.nodes[node].fail
+		}
+		if next, ok := a.nodes[node].next[class]; ok {
+			node = next
+		}
+
+		for _, word := range a.nodes[node].words {
+			found = append(found, candidate{
+				start: offsets[len(offsets)-a.normLens[word]],
+				word:  word,
+			})
+		}
+	}
+
+	return found
+}
+
+// verify matches the word at start with the regexp semantics and returns the
+// span of the match. A collapsed candidate starts at the first letter of a
+// run, so the later letters of the run are tried as well.
+func (a *Automaton) verify(word int32, input string, start int) (int, int, bool) {
+	class := a.class(firstRune(input[start:]))
+	for {
+		if end, ok := a.verifyAt(word, input, start); ok {
+			return start, end, true
+		}
+
+		if !a.collapseRepeats {
+			return 0, 0, false
+		}
+
+		_, size := utf8.DecodeRuneInString(input[start:])
+		start += size
+		for start < len(input) && isSeparator(rune(input[start])) {
+			start++
+		}
+
+		if start == len(input) || a.class(firstRune(input[start:])) != class {
+			return 0, 0, false
+		}
+	}
+}
+
+func firstRune(str string) rune {
+	r, _ := utf8.DecodeRuneInString(str)
+	return r
+}
+
+func (a *Automaton) verifyAt(word int32, input string, start int) (int, bool) {
+	end, ok := matchRuns(a.runs[word], input, start)
+	if !ok {
+		return 0, false
+	}
+
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(input[:start]); unicode.IsLetter(r) {
+			return 0, false
+		}
+	}
+
+	if end < len(input) {
+		if r, _ := utf8.DecodeRuneInString(input[end:]); unicode.IsLetter(r) {
+			return 0, false
+		}
+	}
+
+	return end, true
+}
+
+func matchRuns(runs []letterRun, input string, pos int) (int, bool) {
+	run := runs[0]
+
+	// ends[k] is the end of the first k+1 letters of the run; the letters
+	// of a repeated run may be separated just like the letters of a word.
+	ends := []int{}
+	for len(ends) < run.min || run.repeat {
+		r, size := utf8.DecodeRuneInString(input[pos:])
+		if size == 0 || !slices.Contains(run.allowed, foldRune(r)) {
+			break
+		}
+
+		pos += size
+		ends = append(ends, pos)
+		for pos < len(input) && isSeparator(rune(input[pos])) {
+			pos++
+		}
+	}
+
+	// The run is consumed greedily and given back letter by letter.
+	for k := len(ends); k >= run.min && k > 0; k-- {
+		runEnd := ends[k-1]
+		if len(runs) == 1 {
+			return runEnd, true
+		}
+
+		// Separators are consumed greedily and given back one by one, like
+		// the [ .\-]* between the letters in the regexp.
+		sepEnd := runEnd
+		for sepEnd < len(input) && isSeparator(rune(input[sepEnd])) {
+			sepEnd++
+		}
+
+		for next := sepEnd; next >= runEnd; next-- {
+			if end, ok := matchRuns(runs[1:], input, next); ok {
+				return end, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func (a *Automaton) Contains(input string) bool {
+	for _, cand := range a.candidates(input) {
+		if _, _, ok := a.verify(cand.word, input, cand.start); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Find reports non-overlapping matches from left to right. Of the words
+// starting at the same offset the one listed first in the dictionary wins,
+// as it does in the regexp alternation.
+func (a *Automaton) Find(input string) []Match {
+	cands := a.candidates(input)
+	sort.Slice(cands, func(i, j int) bool {
+		if cands[i].start != cands[j].start {
+			return cands[i].start < cands[j].start
+		}
+
+		return cands[i].word < cands[j].word
+	})
+
+	matches := []Match{}
+	prevEnd := 0
+	for _, cand := range cands {
+		if cand.start < prevEnd {
+			continue
+		}
+
+		start, end, ok := a.verify(cand.word, input, cand.start)
+		if !ok {
+			continue
+		}
+
+		matches = append(matches, Match{Start: start, End: end, Word: a.words[cand.word]})
+		prevEnd = end
+	}
+
+	return matches
+}
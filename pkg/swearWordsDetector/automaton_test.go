@@ -0,0 +1,130 @@
+package swearWordsDetector
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var benchInputs = map[string]string{
+	"username":        "fluffy_kitty_2003",
+	"login_cyrillic":  "котик_мурзик",
+	"contacts":        "tg: @rex_owner, +7 (999) 123-45-67, rex.owner@mail.ru",
+	"pet_info":        "Rex is a three year old golden retriever. He loves long walks in the park, chasing balls and swimming in the lake. Vaccinated, neutered, gets along well with kids and cats.",
+	"pet_info_ru":     "Мурзик — спокойный кот четырёх лет. Любит спать на подоконнике и играть с мышками. Привит, кастрирован, приучен к лотку.",
+	"description":     strings.Repeat("Experienced dog sitter, I will walk your dog twice a day, feed it and send photos. Available on weekends and holidays. ", 8),
+	"swear_plain":     "what the fuck is this service",
+	"swear_spaced":    "you are a f.u.c-k i n g joke",
+	"swear_homoglyph": "sh1t h@ppens, бл@ть",
+	"swear_long":      strings.Repeat("Nice dog, good boy. ", 20) + "but the owner is an asshole",
+}
+
+func loadWords(tb testing.TB) []string {
+	tb.Helper()
+
+	words := []string{}
+	for _, path := range []string{englishSwearsPath, russianSwearsPath} {
+		entries, err := (&FileSource{Path: path}).Load()
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		for _, entry := range entries {
+			words = append(words, entry.Word)
+		}
+	}
+
+	return words
+}
+
+func newMatchers(tb testing.TB) (*RegexpMatcher, *Automaton) {
+	tb.Helper()
+
+	words := loadWords(tb)
+	regexpMatcher, err := NewRegexpMatcher(words)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return regexpMatcher, NewAutomaton(words, false)
+}
+
+// spellOut puts a dot between the letters of the word.
+func spellOut(word string) string {
+	return strings.Join(strings.Split(word, ""), ".")
+}
+
+func TestAutomatonMatchesRegexp(t *testing.T) {
+	regexpMatcher, automaton := newMatchers(t)
+
+	for name, input := range benchInputs {
+		expected, got := regexpMatcher.Find(input), automaton.Find(input)
+		if !reflect.DeepEqual(expected, got) {
+			t.Errorf("%s:\n\tregexp:    %+v\n\tautomaton: %+v", name, expected, got)
+		}
+	}
+
+	// Every dictionary word on its own, spelled with separators and glued to
+	// a letter must be detected by both matchers in the same way.
+	for _, word := range loadWords(t) {
+		for _, input := range []string{word, "(" + spellOut(word) + ")", "x" + word} {
+			if expected, got := regexpMatcher.Contains(input), automaton.Contains(input); expected != got {
+				t.Errorf("%q: regexp %v, automaton %v", input, expected, got)
+			}
+		}
+	}
+}
+
+func BenchmarkContains(b *testing.B) {
+	regexpMatcher, automaton := newMatchers(b)
+
+	for name, input := range benchInputs {
+		b.Run("regexp/"+name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				regexpMatcher.Contains(input)
+			}
+		})
+
+		b.Run("automaton/"+name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				automaton.Contains(input)
+			}
+		})
+	}
+}
+
+func BenchmarkFind(b *testing.B) {
+	regexpMatcher, automaton := newMatchers(b)
+
+	for name, input := range benchInputs {
+		b.Run("regexp/"+name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				regexpMatcher.Find(input)
+			}
+		})
+
+		b.Run("automaton/"+name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				automaton.Find(input)
+			}
+		})
+	}
+}
+
+func BenchmarkBuild(b *testing.B) {
+	words := loadWords(b)
+
+	b.Run("regexp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := NewRegexpMatcher(words); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("automaton", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewAutomaton(words, false)
+		}
+	})
+}
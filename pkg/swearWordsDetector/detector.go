@@ -1,70 +1,325 @@
 package swearWordsDetector
 
 import (
-	"bufio"
-	"os"
-	"regexp"
-	"slices"
+	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 )
 
-var re *regexp.Regexp
+type Severity string
 
-func uploadSwearsList() ([]string, error) {
-	var swearWordsList []string
+const (
+	// SeverityBlock words make the input rejected.
+	SeverityBlock Severity = "block"
+	// SeverityFlag words let the input through but bring it to moderators.
+	SeverityFlag Severity = "flag"
+)
 
-	english_file, err := os.Open("assets/swears/english_swears.txt")
-	if err != nil {
-		return nil, err
+func isSeverity(severity Severity) bool {
+	return severity == SeverityBlock || severity == SeverityFlag
+}
+
+// ParseSeverity reads a severity, an empty string standing for SeverityBlock.
+func ParseSeverity(str string) (Severity, error) {
+	if str == "" {
+		return SeverityBlock, nil
 	}
-	defer english_file.Close()
 
-	scanner := bufio.NewScanner(english_file)
-	for scanner.Scan() {
-		swearWordsList = append(swearWordsList, scanner.Text())
+	severity := Severity(str)
+	if !isSeverity(severity) {
+		return "", BAD_SEVERITY_ERR
 	}
 
-	if err = scanner.Err(); err != nil {
-		return nil, err
+	return severity, nil
+}
+
+// Entry is a dictionary word or phrase.
+type Entry struct {
+	Word     string
+	Language string
+	Severity Severity
+	// Allowed marks a false positive such as a place name or a surname:
+	// matches lying inside it are not reported.
+	Allowed bool
+}
+
+// Match is a dictionary word found in an input.
+type Match struct {
+	// Input is the index of the input among the ones passed to FindInMultipleInputs.
+	Input int
+	// Field is the name of the input passed to FindInFields.
+	Field string
+	// Start and End are the byte offsets of the match in the input.
+	Start int
+	End   int
+	// Word is the dictionary word that has been matched.
+	Word     string
+	Language string
+	Severity Severity
+}
+
+// Field is a named input checked by FindInFields.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Matcher finds dictionary words in inputs.
+type Matcher interface {
+	Contains(input string) bool
+	Find(input string) []Match
+}
+
+type dictionary struct {
+	denied  *Automaton
+	allowed *Automaton
+	// entries are keyed by the normalized words and their transliterations
+	entries map[string]Entry
+}
+
+// Detector finds words from its sources in user input. The dictionary is
+// swapped atomically on reload, so a detector may be used concurrently.
+type Detector struct {
+	normalizer *Normalizer
+	sources    []Source
+
+	dict     atomic.Pointer[dictionary]
+	reloadMu sync.Mutex
+	versions []string
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func NewDetector(normalizer *Normalizer, sources ...Source) (*Detector, error) {
+	detector := &Detector{
+		normalizer: normalizer,
+		sources:    sources,
+		closed:     make(chan struct{}),
 	}
 
-	russian_file, err := os.Open("assets/swears/russian_swears.txt")
+	err := detector.Reload()
 	if err != nil {
 		return nil, err
 	}
-	defer russian_file.Close()
 
-	scanner = bufio.NewScanner(russian_file)
-	for scanner.Scan() {
-		swearWordsList = append(swearWordsList, scanner.Text())
+	return detector, nil
+}
+
+// Reload reads all sources and replaces the dictionary.
+func (d *Detector) Reload() error {
+	d.reloadMu.Lock()
+	defer d.reloadMu.Unlock()
+
+	versions := make([]string, len(d.sources))
+	denied, allowed := []string{}, []string{}
+	entries := map[string]Entry{}
+
+	for i, src := range d.sources {
+		version, err := src.Version()
+		if err != nil {
+			return err
+		}
+		versions[i] = version
+
+		srcEntries, err := src.Load()
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range srcEntries {
+			entry.Word = strings.ToLower(strings.TrimSpace(entry.Word))
+			if entry.Word == "" {
+				continue
+			}
+
+			if entry.Allowed {
+				allowed = append(allowed, d.normalizer.Normalize(entry.Word).Text)
+				continue
+			}
+
+			spellings := append([]string{entry.Word}, transliterations(entry.Word)...)
+			for _, spelling := range spellings {
+				key := d.normalizer.Normalize(spelling).Text
+
+				// A word listed twice keeps the stricter severity.
+				if prev, ok := entries[key]; ok {
+					if prev.Severity == SeverityFlag && entry.Severity == SeverityBlock {
+						entries[key] = entry
+					}
+					continue
+				}
+
+				entries[key] = entry
+				denied = append(denied, key)
+			}
+		}
 	}
 
-	if err = scanner.Err(); err != nil {
-		return nil, err
+	d.dict.Store(&dictionary{
+		denied:  NewAutomaton(denied, true),
+		allowed: NewAutomaton(allowed, true),
+		entries: entries,
+	})
+	d.versions = versions
+
+	return nil
+}
+
+// Watch polls the sources every interval and reloads the dictionary when any
+// of them changes, until the detector is closed. A failed reload keeps the
+// previous dictionary and is passed to onError.
+func (d *Detector) Watch(interval time.Duration, onError func(err error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-d.closed:
+				return
+			case <-ticker.C:
+				if d.changed() {
+					if err := d.Reload(); err != nil {
+						onError(fmt.Errorf("%w: %v", RELOAD_ERR, err))
+					}
+				}
+			}
+		}
+	}()
+}
+
+func (d *Detector) changed() bool {
+	d.reloadMu.Lock()
+	defer d.reloadMu.Unlock()
+
+	for i, src := range d.sources {
+		version, err := src.Version()
+		if err != nil || version != d.versions[i] {
+			return true
+		}
 	}
 
-	return swearWordsList, nil
+	return false
+}
+
+func (d *Detector) Close() {
+	d.closeOnce.Do(func() {
+		close(d.closed)
+	})
 }
 
-func buildRegexpPattern(swearWordsList []string) string {
-	var variations []string
-	for _, word := range swearWordsList {
-		variation := ""
-		for _, char := range word {
-			escapedChar := regexp.QuoteMeta(string(char))
-			variation += "[" + escapedChar
-			subs := getSubstitution(char)
-			for _, sub := range subs {
-				variation += string(sub)
+func (d *Detector) Contains(input string) bool {
+	return len(d.Find(input)) != 0
+}
+
+// Find returns the dictionary words found in the input except the ones lying
+// inside allowlisted phrases. Spans refer to the original input, while words
+// are matched in its normalized form.
+func (d *Detector) Find(input string) []Match {
+	dict := d.dict.Load()
+	normalized := d.normalizer.Normalize(input)
+
+	found := dict.denied.Find(normalized.Text)
+	if len(found) == 0 {
+		return found
+	}
+
+	allowedSpans := dict.allowed.Find(normalized.Text)
+
+	matches := []Match{}
+	for _, match := range found {
+		isAllowed := false
+		for _, span := range allowedSpans {
+			if span.Start <= match.Start && match.End <= span.End {
+				isAllowed = true
+				break
 			}
-			variation += "][ .\\-]*"
 		}
-		variation = strings.TrimSuffix(variation, "[ .\\-]*")
-		variations = append(variations, variation)
+		if isAllowed {
+			continue
+		}
+
+		entry := dict.entries[match.Word]
+		match.Start, match.End = normalized.Span(match.Start, match.End)
+		match.Word = entry.Word
+		match.Language = entry.Language
+		match.Severity = entry.Severity
+		matches = append(matches, match)
+	}
+
+	return matches
+}
+
+// FindInMultipleInputs returns the matches of all inputs with Match.Input set.
+func (d *Detector) FindInMultipleInputs(inputs ...string) []Match {
+	results := make([][]Match, len(inputs))
+	wg := &sync.WaitGroup{}
+
+	for job_number, input := range inputs {
+		wg.Add(1)
+		go func(i int, in string) {
+			defer wg.Done()
+
+			results[i] = d.Find(in)
+			for j := range results[i] {
+				results[i][j].Input = i
+			}
+		}(job_number, input)
+	}
+
+	wg.Wait()
+
+	matches := []Match{}
+	for _, inputMatches := range results {
+		matches = append(matches, inputMatches...)
 	}
 
-	return strings.Join(variations, "|")
+	return matches
+}
+
+// FindInFields returns the matches of all fields with Match.Field set.
+func (d *Detector) FindInFields(fields ...Field) []Match {
+	inputs := make([]string, len(fields))
+	for i, field := range fields {
+		inputs[i] = field.Value
+	}
+
+	matches := d.FindInMultipleInputs(inputs...)
+	for i := range matches {
+		matches[i].Field = fields[matches[i].Input].Name
+	}
+
+	return matches
+}
+
+// Mask replaces every character of the found words with an asterisk.
+func (d *Detector) Mask(input string) string {
+	return MaskMatches(input, d.Find(input))
+}
+
+// MaskMatches replaces every character of the given matches with an asterisk.
+func MaskMatches(input string, matches []Match) string {
+	if len(matches) == 0 {
+		return input
+	}
+
+	var masked strings.Builder
+	prevEnd := 0
+	for _, match := range matches {
+		// matches found in one NFKC segment, like a ligature, may overlap
+		start := max(match.Start, prevEnd)
+		end := max(match.End, start)
+		masked.WriteString(input[prevEnd:start])
+		masked.WriteString(strings.Repeat("*", utf8.RuneCountInString(input[start:end])))
+		prevEnd = end
+	}
+	masked.WriteString(input[prevEnd:])
+
+	return masked.String()
 }
 
 func getSubstitution(char rune) []rune {
@@ -113,48 +368,3 @@ func getSubstitution(char rune) []rune {
 		return []rune{char}
 	}
 }
-
-func BuildAndCompileRegexp() error {
-	word_list, err := uploadSwearsList()
-	if err != nil {
-		return err
-	}
-
-	pattern := `(?i)(^|[\P{L}])(` + buildRegexpPattern(word_list) + `)($|[\P{L}])`
-	re = regexp.MustCompile(pattern)
-
-	return nil
-}
-
-func ContainsSwearWords(input string) bool {
-	return re.MatchString(input)
-}
-
-func DetectInMultipleInputs(inputs ...string) bool {
-	results := make([]bool, len(inputs))
-	wg := &sync.WaitGroup{}
-
-	for job_number, input := range inputs {
-		wg.Add(1)
-		go func(i int, in string) {
-			defer wg.Done()
-
-			contains := ContainsSwearWords(in)
-			results[i] = contains
-		}(job_number, input)
-	}
-
-	wg.Wait()
-
-	return slices.Contains(results, true)
-}
-
-/*func main() {
-	err := BuildAndCompileRegexp()
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-
-	fmt.Println(DetectInMultipleInputs("привет", "хй", "fu"))
-}*/
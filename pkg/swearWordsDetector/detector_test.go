@@ -0,0 +1,99 @@
+package swearWordsDetector
+
+import (
+	"bufio"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	englishSwearsPath = "../../assets/swears/english_swears.txt"
+	russianSwearsPath = "../../assets/swears/russian_swears.txt"
+	allowlistPath     = "../../assets/swears/allowlist.txt"
+	confusablesPath   = "../../assets/swears/confusables.txt"
+	evasionsPath      = "../../assets/swears/evasions.txt"
+)
+
+func newTestDetector(t *testing.T) *Detector {
+	t.Helper()
+
+	confusables, err := LoadConfusables(confusablesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detector, err := NewDetector(NewNormalizer(confusables),
+		&FileSource{Language: "en", Path: englishSwearsPath, Severity: SeverityBlock},
+		&FileSource{Language: "ru", Path: russianSwearsPath, Severity: SeverityBlock},
+		&FileSource{Language: "any", Path: allowlistPath, Severity: SeverityBlock, Allowed: true},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(detector.Close)
+
+	return detector
+}
+
+type evasionCase struct {
+	line     int
+	expected string
+	input    string
+}
+
+// loadEvasions reads the corpus lines written as the expected dictionary
+// word, or - for clean text, a tab and the Go-quoted input.
+func loadEvasions(t *testing.T) []evasionCase {
+	t.Helper()
+
+	file, err := os.Open(evasionsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	cases := []evasionCase{}
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		expected, quoted, found := strings.Cut(line, "\t")
+		input, err := strconv.Unquote(strings.TrimSpace(quoted))
+		if !found || err != nil {
+			t.Fatalf("%s:%d: expected 'word<TAB>\"input\"'", evasionsPath, lineNum)
+		}
+
+		cases = append(cases, evasionCase{line: lineNum, expected: expected, input: input})
+	}
+
+	if err = scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return cases
+}
+
+func TestEvasionCorpus(t *testing.T) {
+	detector := newTestDetector(t)
+
+	for _, tc := range loadEvasions(t) {
+		t.Run(strconv.Itoa(tc.line), func(t *testing.T) {
+			words := []string{}
+			for _, match := range detector.Find(tc.input) {
+				words = append(words, match.Word)
+			}
+
+			if tc.expected == "-" && len(words) != 0 {
+				t.Errorf("%q: expected clean text, found %v", tc.input, words)
+			} else if tc.expected != "-" && !slices.Contains(words, tc.expected) {
+				t.Errorf("%q: expected %s, found %v", tc.input, tc.expected, words)
+			}
+		})
+	}
+}
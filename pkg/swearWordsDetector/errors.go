@@ -0,0 +1,12 @@
+package swearWordsDetector
+
+import "fmt"
+
+var (
+	BAD_SEVERITY_ERR = fmt.Errorf("invalid severity: must be 'block' or 'flag'")
+	BAD_SOURCE_ERR   = fmt.Errorf("invalid dictionary source: expected 'lang:path' or 'lang:path:severity'")
+	EMPTY_WORD_ERR   = fmt.Errorf("word must be non-empty")
+	RELOAD_ERR       = fmt.Errorf("failed to reload swear words")
+
+	BAD_CONFUSABLE_ERR = fmt.Errorf("invalid confusable: expected 'source ; target ; type' with hex code points")
+)
@@ -0,0 +1,154 @@
+package swearWordsDetector
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalizer prepares text for matching: it applies NFKC, which turns
+// fullwidth, mathematical and other compatibility forms into plain letters,
+// drops invisible format characters and stray combining marks, replaces
+// confusables with the letters they imitate and folds case.
+// Dictionary words go through the same steps as user input.
+type Normalizer struct {
+	confusables map[rune]string
+}
+
+func NewNormalizer(confusables map[rune]string) *Normalizer {
+	return &Normalizer{confusables: confusables}
+}
+
+// Normalized is a normalized text remembering where every byte of it came
+// from in the original input.
+type Normalized struct {
+	Text   string
+	starts []int
+	ends   []int
+}
+
+// Span converts a byte span of the normalized text to the original input.
+func (n Normalized) Span(start, end int) (int, int) {
+	return n.starts[start], n.ends[end-1]
+}
+
+func (n *Normalizer) Normalize(input string) Normalized {
+	result := Normalized{
+		starts: make([]int, 0, len(input)),
+		ends:   make([]int, 0, len(input)),
+	}
+
+	var text strings.Builder
+	text.Grow(len(input))
+
+	write := func(str string, start, end int) {
+		text.WriteString(str)
+		for i := 0; i < len(str); i++ {
+			result.starts = append(result.starts, start)
+			result.ends = append(result.ends, end)
+		}
+	}
+
+	// NFKC works on segments of a starter and its combining marks, so every
+	// rune it returns is mapped back to the whole segment.
+	var iter norm.Iter
+	iter.InitString(norm.NFKC, input)
+	for !iter.Done() {
+		start := iter.Pos()
+		segment := string(iter.Next())
+		end := iter.Pos()
+
+		for _, r := range segment {
+			if isInvisible(r) {
+				continue
+			}
+
+			if replacement, ok := n.confusables[r]; ok {
+				write(strings.ToLower(replacement), start, end)
+				continue
+			}
+
+			write(string(unicode.ToLower(r)), start, end)
+		}
+	}
+
+	result.Text = text.String()
+
+	return result
+}
+
+// isInvisible reports whether r is a zero-width or other format character,
+// or a combining mark left after composition, e.g. a strikethrough.
+func isInvisible(r rune) bool {
+	return unicode.In(r, unicode.Cf, unicode.Mn, unicode.Me)
+}
+
+// LoadConfusables reads a file in the format of the Unicode confusables.txt:
+// "source ; target ; type # comment" with code points in hex. ASCII sources
+// are skipped since look-alike digits and symbols are handled by the
+// substitution sets, which know what letter they stand for.
+func LoadConfusables(path string) (map[rune]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	confusables := map[rune]string{}
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNum, BAD_CONFUSABLE_ERR)
+		}
+
+		source, err := parseCodePoints(fields[0])
+		if err != nil || utf8.RuneCountInString(source) != 1 {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNum, BAD_CONFUSABLE_ERR)
+		}
+
+		target, err := parseCodePoints(fields[1])
+		if err != nil || target == "" {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNum, BAD_CONFUSABLE_ERR)
+		}
+
+		r, _ := utf8.DecodeRuneInString(source)
+		if r < utf8.RuneSelf {
+			continue
+		}
+
+		confusables[r] = target
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return confusables, nil
+}
+
+func parseCodePoints(str string) (string, error) {
+	var result strings.Builder
+	for _, hex := range strings.Fields(str) {
+		codePoint, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return "", err
+		}
+
+		result.WriteRune(rune(codePoint))
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,80 @@
+package swearWordsDetector
+
+import (
+	"regexp"
+	"strings"
+)
+
+// firstWordGroup is the index of the capture group of the first word: groups
+// 1 and 2 are the leading boundary and the whole alternation.
+const firstWordGroup = 3
+
+// RegexpMatcher compiles the whole dictionary into one alternation with a
+// character class per letter. It is kept as a reference for the automaton.
+type RegexpMatcher struct {
+	re    *regexp.Regexp
+	words []string
+}
+
+func NewRegexpMatcher(words []string) (*RegexpMatcher, error) {
+	pattern := `(?i)(^|[\P{L}])(` + buildRegexpPattern(words) + `)($|[\P{L}])`
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegexpMatcher{re: re, words: words}, nil
+}
+
+func buildRegexpPattern(swearWordsList []string) string {
+	var variations []string
+	for _, word := range swearWordsList {
+		variation := ""
+		for _, char := range word {
+			escapedChar := regexp.QuoteMeta(string(char))
+			variation += "[" + escapedChar
+			subs := getSubstitution(char)
+			for _, sub := range subs {
+				variation += string(sub)
+			}
+			variation += "][ .\\-]*"
+		}
+		variation = strings.TrimSuffix(variation, "[ .\\-]*")
+		variations = append(variations, "("+variation+")")
+	}
+
+	return strings.Join(variations, "|")
+}
+
+func (m *RegexpMatcher) Contains(input string) bool {
+	return m.re.MatchString(input)
+}
+
+func (m *RegexpMatcher) Find(input string) []Match {
+	matches := []Match{}
+
+	for pos := 0; pos <= len(input); {
+		loc := m.re.FindStringSubmatchIndex(input[pos:])
+		if loc == nil {
+			break
+		}
+
+		match := Match{Start: pos + loc[4], End: pos + loc[5]}
+		for group := firstWordGroup; 2*group < len(loc); group++ {
+			if loc[2*group] >= 0 {
+				match.Word = m.words[group-firstWordGroup]
+				break
+			}
+		}
+		matches = append(matches, match)
+
+		// The trailing boundary may be the leading one of the next word, so
+		// the search continues right after the matched word.
+		pos = match.End
+		if loc[5] == loc[4] {
+			pos++
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,97 @@
+package swearWordsDetector
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Source provides dictionary entries to the detector.
+type Source interface {
+	Load() ([]Entry, error)
+	// Version changes whenever the entries do, so that Watch can tell when
+	// the detector has to be reloaded.
+	Version() (string, error)
+}
+
+// FileSource reads one word or phrase per line. '#' starts a comment, and a
+// line may override the severity of the file after a tab: "word\tflag".
+type FileSource struct {
+	Path     string
+	Language string
+	Severity Severity
+	Allowed  bool
+}
+
+func (src *FileSource) Load() ([]Entry, error) {
+	file, err := os.Open(src.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		entry := Entry{Word: line, Language: src.Language, Severity: src.Severity, Allowed: src.Allowed}
+		if word, severity, found := strings.Cut(line, "\t"); found {
+			entry.Word = word
+			entry.Severity = Severity(strings.TrimSpace(severity))
+			if !isSeverity(entry.Severity) {
+				return nil, fmt.Errorf("%s: %w: %q", src.Path, BAD_SEVERITY_ERR, severity)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (src *FileSource) Version() (string, error) {
+	info, err := os.Stat(src.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d:%d", info.ModTime().UnixNano(), info.Size()), nil
+}
+
+// ParseFileSources reads sources written as "lang:path[:severity]" separated
+// by commas, e.g. "en:assets/swears/english_swears.txt,ru:assets/swears/russian_swears.txt:flag".
+func ParseFileSources(spec string, allowed bool) ([]Source, error) {
+	sources := []Source{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Split(part, ":")
+		if len(fields) < 2 || len(fields) > 3 || fields[1] == "" {
+			return nil, fmt.Errorf("%w: %q", BAD_SOURCE_ERR, part)
+		}
+
+		src := &FileSource{Language: fields[0], Path: fields[1], Severity: SeverityBlock, Allowed: allowed}
+		if len(fields) == 3 {
+			src.Severity = Severity(fields[2])
+			if !isSeverity(src.Severity) {
+				return nil, fmt.Errorf("%w: %q", BAD_SEVERITY_ERR, part)
+			}
+		}
+
+		sources = append(sources, src)
+	}
+
+	return sources, nil
+}
@@ -0,0 +1,149 @@
+package swearWordsDetector
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minLatinTransliterationLen keeps short English words from being spelled in
+// Cyrillic, where they tend to be ordinary Russian words ("cum" and "кум").
+const minLatinTransliterationLen = 5
+
+// minCyrillicTransliterationLen does the same for Russian words spelled in
+// Latin. It is lower, since the short Russian swears are the ones spelled in
+// Latin the most, and their spellings are checked against englishWords.
+const minCyrillicTransliterationLen = 3
+
+// minSpellingLen is the length of the shortest spelling added in either
+// direction, two letters being a word in too many languages.
+const minSpellingLen = 3
+
+// englishWords are ordinary English words some Russian words are spelled
+// as in Latin ("хер" and "her"). Such spellings are not added to the
+// dictionary.
+var englishWords = map[string]struct{}{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		her hers here herb herd hero hen hey hue hug hub hum hut huh hull
+		his him hip hit hot how hoe hose host hold hole home hook hope horn
+		lox lot low pot pots pod pop pope poke pole poll pony pose post
+		mocha mock mod mode mom mop mob more
+		abort debut sue suit such sock sick sack sum sun sup
+		blue bloc blog bled bless kit kite war tar
+		man mane many mint mine pit pie pies
+		gun gum gob god good the they them then
+	`) {
+		englishWords[word] = struct{}{}
+	}
+}
+
+// cyrillicToLatin lists the usual spellings of Russian letters in Latin, the
+// most common one first.
+var cyrillicToLatin = map[string][]string{
+	"а": {"a"}, "б": {"b"}, "в": {"v", "w"}, "г": {"g"}, "д": {"d"},
+	"е": {"e"}, "ё": {"e", "yo"}, "ж": {"zh", "j"}, "з": {"z"}, "и": {"i"},
+	"й": {"y", "i", "j"}, "к": {"k"}, "л": {"l"}, "м": {"m"}, "н": {"n"},
+	"о": {"o"}, "п": {"p"}, "р": {"r"}, "с": {"s"}, "т": {"t"},
+	"у": {"u", "y"}, "ф": {"f"}, "х": {"h", "kh", "x"}, "ц": {"ts", "c"}, "ч": {"ch"},
+	"ш": {"sh"}, "щ": {"sch", "sh"}, "ъ": {""}, "ы": {"y", "i"}, "ь": {""},
+	"э": {"e"}, "ю": {"yu", "ju"}, "я": {"ya", "ja"},
+}
+
+// latinToCyrillic spells English words the way they are written in Cyrillic.
+var latinToCyrillic = map[string][]string{
+	"sh": {"ш"}, "ch": {"ч"}, "ck": {"к"}, "th": {"т"}, "ph": {"ф"},
+	"kh": {"х"}, "oo": {"у"}, "ee": {"и"}, "zh": {"ж"}, "ts": {"ц"},
+	"a": {"а", "э"}, "b": {"б"}, "c": {"к", "с"}, "d": {"д"}, "e": {"е", "и"},
+	"f": {"ф"}, "g": {"г"}, "h": {"х"}, "i": {"и", "ай"}, "j": {"дж"},
+	"k": {"к"}, "l": {"л"}, "m": {"м"}, "n": {"н"}, "o": {"о"},
+	"p": {"п"}, "q": {"к"}, "r": {"р"}, "s": {"с"}, "t": {"т"},
+	"u": {"а", "у"}, "v": {"в"}, "w": {"в", "у"}, "x": {"кс"}, "y": {"и", "й"},
+	"z": {"з"},
+}
+
+// transliterations returns spellings of a single Russian word in Latin or of
+// a single English word in Cyrillic. Phrases are left as they are.
+func transliterations(word string) []string {
+	if strings.ContainsFunc(word, isSeparator) {
+		return nil
+	}
+
+	table, minLen := latinToCyrillic, minLatinTransliterationLen
+	if strings.ContainsFunc(word, func(r rune) bool { return unicode.Is(unicode.Cyrillic, r) }) {
+		table, minLen = cyrillicToLatin, minCyrillicTransliterationLen
+	}
+
+	if utf8.RuneCountInString(word) < minLen {
+		return nil
+	}
+
+	// The word is split into letters and digraphs with their spellings.
+	letters := [][]string{}
+	for rest := word; rest != ""; {
+		// digraphs are looked up before single letters
+		key := ""
+		for _, size := range []int{2, 1} {
+			if prefix := runePrefix(rest, size); table[prefix] != nil {
+				key = prefix
+				break
+			}
+		}
+
+		alternatives := table[key]
+		if key == "" {
+			_, size := utf8.DecodeRuneInString(rest)
+			key = rest[:size]
+			alternatives = []string{key}
+		}
+
+		letters = append(letters, alternatives)
+		rest = rest[len(key):]
+	}
+
+	// The usual spelling goes first, followed by the ones differing from it
+	// in a single letter, which keeps the dictionary from growing
+	// exponentially with the length of the words.
+	usual := make([]string, len(letters))
+	for i, alternatives := range letters {
+		usual[i] = alternatives[0]
+	}
+
+	spellings := []string{strings.Join(usual, "")}
+	for i, alternatives := range letters {
+		for _, alternative := range alternatives[1:] {
+			variant := slices.Clone(usual)
+			variant[i] = alternative
+			spellings = append(spellings, strings.Join(variant, ""))
+		}
+	}
+
+	// Spellings too short or colliding with English words would report
+	// ordinary text.
+	variants := []string{}
+	for _, spelling := range spellings {
+		if _, english := englishWords[spelling]; english || utf8.RuneCountInString(spelling) < minSpellingLen {
+			continue
+		}
+
+		variants = append(variants, spelling)
+	}
+
+	return variants
+}
+
+// runePrefix returns the first n runes of str or "" if it is shorter.
+func runePrefix(str string, n int) string {
+	end := 0
+	for i := 0; i < n; i++ {
+		_, size := utf8.DecodeRuneInString(str[end:])
+		if size == 0 {
+			return ""
+		}
+		end += size
+	}
+
+	return str[:end]
+}
@@ -0,0 +1,44 @@
+package textLanguage
+
+import "unicode"
+
+// Language is the name MongoDB text indexes take for stemming and stop
+// words.
+type Language string
+
+const (
+	Russian Language = "russian"
+	English Language = "english"
+	// None turns stemming off, for texts without letters.
+	None Language = "none"
+)
+
+func IsLanguage(language Language) bool {
+	return language == Russian || language == English || language == None
+}
+
+// Detect tells the language of the texts by the script most of their letters
+// are written in. Listings mix in Latin brand names and Cyrillic slang, so a
+// text counts as English only when Latin letters clearly prevail.
+func Detect(texts ...string) Language {
+	cyrillic, latin := 0, 0
+	for _, text := range texts {
+		for _, r := range text {
+			switch {
+			case unicode.Is(unicode.Cyrillic, r):
+				cyrillic++
+			case unicode.Is(unicode.Latin, r):
+				latin++
+			}
+		}
+	}
+
+	switch {
+	case cyrillic == 0 && latin == 0:
+		return None
+	case latin > 2*cyrillic:
+		return English
+	default:
+		return Russian
+	}
+}
@@ -0,0 +1,66 @@
+package textLanguage
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minStemLen keeps short words from being cut down to nothing.
+const minStemLen = 3
+
+// The endings are tried longest first. This is far from a full Snowball
+// stemmer, but the same word forms end up with the same stem, which is all
+// highlighting needs.
+var (
+	russianEndings = []string{
+		"иями", "ость", "ости", "ться", "ющий", "ящий", "вший",
+		"ами", "ями", "ией", "ием", "иях", "ого", "его", "ому", "ему", "ыми", "ими",
+		"ешь", "ишь", "ете", "ите", "ала", "ила", "ать", "ять", "ить", "еть",
+		"ах", "ях", "ов", "ев", "ей", "ой", "ий", "ый", "ая", "яя", "ое", "ее",
+		"ие", "ые", "ую", "юю", "ом", "ем", "ам", "ям", "ет", "ит", "ут", "ют",
+		"ат", "ят", "ла", "ло", "ли", "ся", "сь",
+		"а", "я", "о", "е", "ы", "и", "у", "ю", "ь", "й",
+	}
+	englishEndings = []string{
+		"ational", "ization", "fulness", "ousness", "iveness",
+		"ations", "ation", "ments", "ment", "ness",
+		"ing", "ies", "ied", "ed", "ly", "es", "s", "y",
+	}
+)
+
+// Stem cuts the ending off a lowercased word, by the rules of Russian for
+// Cyrillic words and of English for the rest.
+func Stem(word string) string {
+	word = strings.ReplaceAll(word, "ё", "е")
+
+	if strings.IndexFunc(word, func(r rune) bool { return unicode.Is(unicode.Cyrillic, r) }) >= 0 {
+		return cutEnding(word, russianEndings)
+	}
+
+	word = strings.TrimSuffix(word, "'s")
+	if strings.HasSuffix(word, "ss") {
+		return word
+	}
+
+	stem := cutEnding(word, englishEndings)
+
+	// "sitting" and "sit" share a stem
+	runes := []rune(stem)
+	if len(runes) > minStemLen && len(stem) < len(word) && runes[len(runes)-1] == runes[len(runes)-2] &&
+		!strings.ContainsRune("aeioulsz", runes[len(runes)-1]) {
+		stem = string(runes[:len(runes)-1])
+	}
+
+	return stem
+}
+
+func cutEnding(word string, endings []string) string {
+	length := len([]rune(word))
+	for _, ending := range endings {
+		if strings.HasSuffix(word, ending) && length-len([]rune(ending)) >= minStemLen {
+			return strings.TrimSuffix(word, ending)
+		}
+	}
+
+	return word
+}
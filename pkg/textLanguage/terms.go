@@ -0,0 +1,44 @@
+package textLanguage
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords carry no meaning of their own, so texts sharing them are no more
+// alike.
+var stopWords = map[string]struct{}{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		и в во на с со к ко по за из от до у о об для при без над под про не ни но а или то же ли бы что как
+		это я мы вы ты он она они мой моя мое мои ваш ваша ваше ваши наш наша наше наши весь все всё так уже
+		очень есть быть будет можно также
+		a an the and or but of to in on at for with from by as is are was be been it its this that these those
+		i we you he she they my our your their me us not no do does can will very also have has
+	`) {
+		stopWords[strings.ReplaceAll(word, "ё", "е")] = struct{}{}
+	}
+}
+
+// Terms returns the distinct stems of the lowercased words of the texts but
+// the stop words and numbers.
+func Terms(texts ...string) map[string]struct{} {
+	terms := map[string]struct{}{}
+	for _, text := range texts {
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+
+		for _, word := range words {
+			word = strings.ReplaceAll(word, "ё", "е")
+			if _, stop := stopWords[word]; stop || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+				continue
+			}
+
+			terms[Stem(word)] = struct{}{}
+		}
+	}
+
+	return terms
+}
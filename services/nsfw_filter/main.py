@@ -24,20 +24,67 @@ channel = connection.channel()
 channel.queue_declare(queue='nsfw_validation_queue', durable=True)
 
 
+def to_verdict(prediction):
+    sfw_probability, nsfw_probability = prediction
+
+    return (True, sfw_probability) if sfw_probability > nsfw_probability else (False, nsfw_probability)
+
+
 def nsfw_validator(pil_image: ImageFile):
     image = n2.preprocess_image(pil_image, n2.Preprocessing.SIMPLE)
     inputs = np.expand_dims(image, axis=0)  # Add batch axis (for single image).
 
     predictions = model.predict(inputs, verbose=2)
-    sfw_probability, nsfw_probability = predictions[0]
 
-    return (True, sfw_probability) if sfw_probability > nsfw_probability else (False, nsfw_probability)
+    return to_verdict(predictions[0])
+
+
+def validate_batch(images):
+    """
+    Classifies [{"id": ..., "image": ...}] in a single model call and returns
+    per-image results in the single-image format with the "id" added.
+    """
+    results = []
+    inputs = []
+    positions = []
+
+    for item in images:
+        image_id = item.get("id")
+        base64_image = item.get("image")
+        if not base64_image:
+            results.append({"id": image_id, "error": "image data is required", "code": 400})
+            continue
+
+        try:
+            pil_image = Image.open(BytesIO(base64.b64decode(base64_image)))
+            inputs.append(n2.preprocess_image(pil_image, n2.Preprocessing.SIMPLE))
+            positions.append(len(results))
+            results.append({"id": image_id})
+        except Exception as e:
+            results.append({"id": image_id, "error": str(e), "code": 400})
+
+    if inputs:
+        predictions = model.predict(np.stack(inputs), verbose=2)
+        for position, prediction in zip(positions, predictions):
+            is_safe, confidence = to_verdict(prediction)
+            results[position].update({"is_safe": is_safe, "confidence": float(confidence), "code": 200})
+
+    return results
 
 
 def on_request(ch, method, props, body):
     try:
         data = json.loads(body)
 
+        if "images" in data:
+            res_to_return = json.dumps({"results": validate_batch(data["images"]), "code": 200}).encode('utf-8')
+            ch.basic_publish(exchange='',
+                             routing_key=props.reply_to,
+                             properties=pika.BasicProperties(correlation_id = props.correlation_id,
+                                                             delivery_mode=pika.DeliveryMode.Persistent),
+                             body=res_to_return)
+            return
+
         base64_image = data.get("image")
         if not base64_image:
             err_message = json.dumps({"error": "image data is required", "code": 400}).encode('utf-8')